@@ -0,0 +1,238 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultMaxBatchSize is the most writes coalesced into one request when Cfg.MaxBatchSize isn't set
+const DefaultMaxBatchSize = 100
+
+// BatchOp is one write sent as part of a multi-object request
+type BatchOp struct {
+	// Method is POST to create, PUT to update or DELETE
+	Method string `json:"method"`
+	// Object is the object type, such as record:a, for a create and the _ref otherwise
+	Object string `json:"object"`
+	// Data holds the fields to write, nil for a delete
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// IbBatch sends ops to the WAPI request object in a single call and returns the _ref each op
+// produced, in order. Infoblox applies the ops as one transaction, so if any op fails none are applied.
+func (c *Client) IbBatch(ctx context.Context, ops []BatchOp) ([]string, error) {
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(ops)
+	if err != nil {
+		return nil, fmt.Errorf("Error encoding batch: %s", err)
+	}
+	log.Printf("IbBatch sending %d operations", len(ops))
+	log.Printf("IbBatch request body: %s", body)
+
+	r, err := c.do(ctx, resty.MethodPost, "/request", body)
+	if err != nil {
+		log.Printf("Batch request failed")
+		return nil, err
+	}
+	log.Printf("Response body: \n" + r.String())
+
+	var results []json.RawMessage
+	if err := json.Unmarshal(r.Body(), &results); err != nil {
+		return nil, fmt.Errorf("Error decoding batch response: %s", err)
+	}
+	if len(results) != len(ops) {
+		return nil, fmt.Errorf("Batch of %d operations returned %d results", len(ops), len(results))
+	}
+	refs := make([]string, len(results))
+	for i, result := range results {
+		if refs[i], err = decodeRef(result); err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// write sends a create, update or delete and returns the _ref infoblox replies with.
+// When batching is on the write waits to be coalesced with others into a single request.
+func (c *Client) write(ctx context.Context, op BatchOp) (string, error) {
+	if c.batcher != nil {
+		return c.batcher.submit(ctx, op)
+	}
+	return c.writeNow(ctx, op)
+}
+
+// writeNow sends a single write straight away
+func (c *Client) writeNow(ctx context.Context, op BatchOp) (string, error) {
+	r, err := c.do(ctx, op.Method, "/"+op.Object, []byte(op.Data))
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Response body: \n" + r.String())
+	return decodeRef(r.Body())
+}
+
+// batcher coalesces writes made within window of the first into one multi-object request
+type batcher struct {
+	client  *Client
+	window  time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	pending []*batchCall
+	timer   *time.Timer
+}
+
+// batchCall is a write waiting on the batch it was coalesced into
+type batchCall struct {
+	ctx  context.Context
+	op   BatchOp
+	ref  string
+	err  error
+	done chan struct{}
+}
+
+func (call *batchCall) finish(ref string, err error) {
+	call.ref, call.err = ref, err
+	close(call.done)
+}
+
+func newBatcher(c *Client, window time.Duration, maxSize int) *batcher {
+	if window <= 0 {
+		return nil
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBatchSize
+	}
+	return &batcher{client: c, window: window, maxSize: maxSize}
+}
+
+// submit queues op for the next batch and waits for its result or for ctx to finish.
+// A write abandoned before its batch is sent is left out of it.
+func (b *batcher) submit(ctx context.Context, op BatchOp) (string, error) {
+	call := &batchCall{ctx: ctx, op: op, done: make(chan struct{})}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, call)
+	if len(b.pending) >= b.maxSize {
+		calls := b.take()
+		b.mu.Unlock()
+		go b.flush(calls)
+	} else {
+		if len(b.pending) == 1 {
+			b.timer = time.AfterFunc(b.window, b.flushPending)
+		}
+		b.mu.Unlock()
+	}
+
+	select {
+	case <-call.done:
+		return call.ref, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// take empties the queue, the caller must hold b.mu
+func (b *batcher) take() []*batchCall {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	calls := b.pending
+	b.pending = nil
+	return calls
+}
+
+func (b *batcher) flushPending() {
+	b.mu.Lock()
+	calls := b.take()
+	b.mu.Unlock()
+	b.flush(calls)
+}
+
+// flush sends the queued writes as one request. If infoblox rejects the batch nothing was applied,
+// so each write is sent again on its own and the one at fault gets its own error.
+func (b *batcher) flush(calls []*batchCall) {
+	var live []*batchCall
+	for _, call := range calls {
+		if err := call.ctx.Err(); err != nil {
+			call.finish("", err)
+			continue
+		}
+		live = append(live, call)
+	}
+
+	switch len(live) {
+	case 0:
+		return
+	case 1:
+		live[0].finish(b.client.writeNow(live[0].ctx, live[0].op))
+		return
+	}
+
+	ops := make([]BatchOp, len(live))
+	for i, call := range live {
+		ops[i] = call.op
+	}
+	ctx, cancel := batchContext(live)
+	defer cancel()
+	refs, err := b.client.IbBatch(ctx, ops)
+	if err == nil {
+		for i, call := range live {
+			call.finish(refs[i], nil)
+		}
+		return
+	}
+	if !batchRejected(err) {
+		// the batch may have been applied so resending could write twice
+		for _, call := range live {
+			call.finish("", err)
+		}
+		return
+	}
+
+	log.Printf("[WARN] batch of %d writes failed, sending them one at a time: %s", len(live), err)
+	var wg sync.WaitGroup
+	for _, call := range live {
+		wg.Add(1)
+		go func(call *batchCall) {
+			defer wg.Done()
+			call.finish(b.client.writeNow(call.ctx, call.op))
+		}(call)
+	}
+	wg.Wait()
+}
+
+// batchContext returns a context for a batch shared by calls, running until the last of their deadlines
+func batchContext(calls []*batchCall) (context.Context, context.CancelFunc) {
+	var latest time.Time
+	for _, call := range calls {
+		deadline, ok := call.ctx.Deadline()
+		if !ok {
+			return context.WithCancel(context.Background())
+		}
+		if deadline.After(latest) {
+			latest = deadline
+		}
+	}
+	return context.WithDeadline(context.Background(), latest)
+}
+
+// batchRejected reports whether infoblox answered a batch without applying it
+func batchRejected(err error) bool {
+	var wapiErr *WAPIError
+	if !errors.As(err, &wapiErr) {
+		return false
+	}
+	return wapiErr.StatusCode < 500 || wapiErr.StatusCode == 503
+}
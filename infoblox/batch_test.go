@@ -0,0 +1,157 @@
+package infoblox_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+// batching has the client gather writes for window into batches of up to maxSize
+func batching(window time.Duration, maxSize int) func(*infoblox.Cfg) {
+	return func(cfg *infoblox.Cfg) {
+		cfg.BatchWindow = window
+		cfg.MaxBatchSize = maxSize
+	}
+}
+
+// createConcurrently creates an A record for each name at once and returns the errors by name
+func createConcurrently(c *infoblox.Client, names []string) map[string]error {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := map[string]error{}
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"name":%q,"ipv4addr":"10.0.0.%d"}`, name, i+1)
+			_, err := c.IbCreateRecord(context.Background(), "a", []byte(body))
+			mu.Lock()
+			errs[name] = err
+			mu.Unlock()
+		}(i, name)
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestBatchOperations(t *testing.T) {
+	c, s := newTestClient(t)
+	ctx := context.Background()
+	existing, err := s.Create("record:a", map[string]interface{}{"name": "old.example.com", "ipv4addr": "10.0.0.9"})
+	if err != nil {
+		t.Fatalf("Create: %s", err)
+	}
+
+	refs, err := c.IbBatch(ctx, []infoblox.BatchOp{
+		{Method: "POST", Object: "record:a", Data: json.RawMessage(`{"name":"one.example.com","ipv4addr":"10.0.0.1"}`)},
+		{Method: "POST", Object: "record:cname", Data: json.RawMessage(`{"name":"www.example.com","canonical":"one.example.com"}`)},
+		{Method: "DELETE", Object: existing},
+	})
+	if err != nil {
+		t.Fatalf("IbBatch: %s", err)
+	}
+	if len(refs) != 3 || refs[2] != existing {
+		t.Fatalf("unexpected refs %v", refs)
+	}
+	if s.Get(refs[0]) == nil || s.Get(refs[1]) == nil || s.Get(existing) != nil {
+		t.Fatalf("batch wasn't applied, refs %v", refs)
+	}
+	if got := s.Requests(); got != 1 {
+		t.Fatalf("expected a single request, got %d", got)
+	}
+}
+
+func TestBatchIsAtomic(t *testing.T) {
+	c, s := newTestClient(t)
+	_, err := c.IbBatch(context.Background(), []infoblox.BatchOp{
+		{Method: "POST", Object: "record:a", Data: json.RawMessage(`{"name":"one.example.com","ipv4addr":"10.0.0.1"}`)},
+		{Method: "POST", Object: "record:a", Data: json.RawMessage(`{"name":"one.example.com","ipv4addr":"10.0.0.1"}`)},
+	})
+	if !infoblox.IsConflict(err) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	if got := len(s.Objects("record:a")); got != 0 {
+		t.Fatalf("expected the failed batch to be rolled back, found %d records", got)
+	}
+}
+
+func TestBatchCoalescesWrites(t *testing.T) {
+	c, s := newTestClient(t, batching(100*time.Millisecond, 0))
+	names := []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com", "e.example.com"}
+
+	for name, err := range createConcurrently(c, names) {
+		if err != nil {
+			t.Fatalf("creating %s: %s", name, err)
+		}
+	}
+	if got := len(s.Objects("record:a")); got != len(names) {
+		t.Fatalf("expected %d records, got %d", len(names), got)
+	}
+	if got := s.Requests(); got != 1 {
+		t.Fatalf("expected the creates to share one request, got %d", got)
+	}
+	if got := s.Batches(); got != 1 {
+		t.Fatalf("expected one batch, got %d", got)
+	}
+}
+
+func TestBatchMaxSize(t *testing.T) {
+	c, s := newTestClient(t, batching(time.Minute, 2))
+	names := []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com"}
+
+	start := time.Now()
+	for name, err := range createConcurrently(c, names) {
+		if err != nil {
+			t.Fatalf("creating %s: %s", name, err)
+		}
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Fatalf("full batches should be sent without waiting for the window")
+	}
+	if got := s.Batches(); got != 2 {
+		t.Fatalf("expected 2 batches of 2, got %d", got)
+	}
+}
+
+func TestBatchFallsBackToSingleWrites(t *testing.T) {
+	c, s := newTestClient(t, batching(100*time.Millisecond, 0))
+	if _, err := s.Create("record:a", map[string]interface{}{"name": "taken.example.com", "ipv4addr": "10.0.0.2"}); err != nil {
+		t.Fatalf("Create: %s", err)
+	}
+	names := []string{"a.example.com", "taken.example.com", "c.example.com"}
+
+	errs := createConcurrently(c, names)
+	if !infoblox.IsConflict(errs["taken.example.com"]) {
+		t.Fatalf("expected the duplicate to fail with a conflict, got %v", errs["taken.example.com"])
+	}
+	for _, name := range []string{"a.example.com", "c.example.com"} {
+		if errs[name] != nil {
+			t.Fatalf("expected %s to be created on its own, got %s", name, errs[name])
+		}
+	}
+	if got := len(s.Objects("record:a")); got != 3 {
+		t.Fatalf("expected 3 records, got %d", got)
+	}
+	if got := s.Requests(); got != 4 {
+		t.Fatalf("expected the batch and one request per write, got %d", got)
+	}
+}
+
+func TestBatchNotResentWhenOutcomeUnknown(t *testing.T) {
+	c, s := newTestClient(t, batching(100*time.Millisecond, 0))
+	s.Drop(1)
+
+	for name, err := range createConcurrently(c, []string{"a.example.com", "b.example.com"}) {
+		if err == nil {
+			t.Fatalf("expected %s to fail with the batch", name)
+		}
+	}
+	if got := s.Requests(); got != 1 {
+		t.Fatalf("expected a dropped batch not to be resent, got %d requests", got)
+	}
+}
@@ -2,9 +2,11 @@
 package infoblox
 
 import (
-	"crypto/tls"
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -18,6 +20,42 @@ type Cfg struct {
 	Password    string
 	TLSVerify   bool
 	Timeout     int
+	// CACertFile and CACertPEM add CAs trusted for the infoblox certificate on top of the system pool
+	CACertFile string
+	CACertPEM  string
+	// ClientCert and ClientKey, given as PEM or a path to a PEM file, authenticate the client over mutual TLS
+	ClientCert string
+	ClientKey  string
+	// MinTLSVersion is the lowest TLS version accepted, one of TLSVersions
+	MinTLSVersion string
+	// Transport replaces the default HTTP transport, mainly so tests can inject fakes.
+	// TLS settings are the responsibility of the supplied transport.
+	Transport http.RoundTripper
+	// Retry controls retries of transient failures, unset fields use DefaultRetryPolicy
+	Retry RetryPolicy
+	// MaxConcurrentRequests caps requests in flight at once, 0 is unlimited
+	MaxConcurrentRequests int
+	// RequestsPerSecond caps the rate requests are started at, 0 is unlimited
+	RequestsPerSecond float64
+	// BatchWindow, when set, coalesces creates, updates and deletes made within the window
+	// into one multi-object request of at most MaxBatchSize writes
+	BatchWindow  time.Duration
+	MaxBatchSize int
+	// PageSize is how many records each search request fetches, 0 uses DefaultPageSize
+	PageSize int
+}
+
+// Client is a WAPI client bound to a single infoblox host.
+// All requests to infoblox go through a Client so behaviour common to every call lives in one place.
+type Client struct {
+	rest        *resty.Client
+	host        string
+	wapiVersion string
+	retry       RetryPolicy
+	throttle    *throttle
+	session     *session
+	batcher     *batcher
+	pageSize    int
 }
 
 func init() {
@@ -25,8 +63,8 @@ func init() {
 	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
 }
 
-// ClientInit establishes default settings on the rest client
-func ClientInit(c *Cfg) (*resty.Client, error) {
+// ClientInit validates the config and builds a Client from it
+func ClientInit(c *Cfg) (*Client, error) {
 	client := resty.New()
 
 	if c.Host == "" {
@@ -39,27 +77,136 @@ func ClientInit(c *Cfg) (*resty.Client, error) {
 		return nil, errors.New("Invalid Password setting")
 	}
 
-	if c.TLSVerify == false {
-		client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	if c.Transport != nil {
+		client.SetTransport(c.Transport)
 	} else {
-		client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: false})
+		tlsCfg, err := tlsConfig(c)
+		if err != nil {
+			return nil, err
+		}
+		client.SetTLSClientConfig(tlsCfg)
 	}
 
-	client.SetBasicAuth(c.Username, c.Password)
+	// the session cookie is managed by the client rather than a cookie jar
+	client.SetCookieJar(nil)
 	client.SetHeader("Content-Type", "application/json")
 	client.SetTimeout(time.Duration(c.Timeout) * time.Second)
 	client.SetHostURL("https://" + c.Host + "/wapi/v" + c.WAPIVersion)
 
-	return client, nil
+	ib := &Client{
+		rest:        client,
+		host:        c.Host,
+		wapiVersion: c.WAPIVersion,
+		retry:       c.Retry.withDefaults(),
+		throttle:    newThrottle(c.MaxConcurrentRequests, c.RequestsPerSecond),
+		session:     &session{username: c.Username, password: c.Password},
+	}
+	ib.batcher = newBatcher(ib, c.BatchWindow, c.MaxBatchSize)
+	if ib.pageSize = c.PageSize; ib.pageSize <= 0 {
+		ib.pageSize = DefaultPageSize
+	}
+	return ib, nil
 }
 
-// IbGetTest sends a GET request to infoblox
-func IbGetTest(c *resty.Client) error {
-	r, err := c.R().Get("")
-	if r.StatusCode() == 401 {
-		return errors.New("Unauthorised: 401")
+// Host returns the infoblox host the client talks to
+func (c *Client) Host() string {
+	return c.host
+}
+
+// WAPIVersion returns the WAPI version the client was configured with
+func (c *Client) WAPIVersion() string {
+	return c.wapiVersion
+}
+
+// BaseURL returns the WAPI endpoint all request paths are relative to
+func (c *Client) BaseURL() string {
+	return c.rest.HostURL
+}
+
+// do sends a request to infoblox, body may be nil. Transient failures are retried following the
+// client's RetryPolicy for as long as ctx allows. Any response with an error status is returned as a *WAPIError.
+func (c *Client) do(ctx context.Context, method string, path string, body []byte) (*resty.Response, error) {
+	for attempt := 1; ; attempt++ {
+		r, err := c.send(ctx, method, path, body)
+		if err == nil {
+			return r, nil
+		}
+
+		status := 0
+		transportErr := err
+		var wapiErr *WAPIError
+		if errors.As(err, &wapiErr) {
+			status = wapiErr.StatusCode
+			transportErr = nil
+		} else if ctx.Err() != nil {
+			// the operation was cancelled or ran out of time
+			return r, err
+		}
+		if attempt >= c.retry.MaxAttempts || !c.retry.retryable(method, status, transportErr) {
+			return r, err
+		}
+
+		retryAfter := ""
+		if r != nil && r.RawResponse != nil {
+			retryAfter = r.Header().Get("Retry-After")
+		}
+		wait := c.retry.backoff(attempt, retryAfter)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			log.Printf("[WARN] %s %s failed and the operation timeout leaves no time to retry", method, path)
+			return r, err
+		}
+		log.Printf("[WARN] %s %s attempt %d of %d failed, retrying in %s: %s", method, path, attempt, c.retry.MaxAttempts, wait, err)
+		if sleep(ctx, wait) != nil {
+			return r, err
+		}
 	}
+}
+
+// send makes a single attempt at a request once the client's throttle allows it.
+// If infoblox rejects the session cookie the request is sent once more with basic auth to log in again,
+// a rejected request was never processed so this is safe for any method.
+func (c *Client) send(ctx context.Context, method string, path string, body []byte) (*resty.Response, error) {
+	if err := c.throttle.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("infoblox %s %s not sent: %w", method, path, err)
+	}
+	defer c.throttle.release()
+
+	cookie := c.session.current()
+	r, err := c.sendAs(ctx, method, path, body, cookie)
+	if cookie != nil && IsUnauthorized(err) {
+		c.session.expire(cookie)
+		r, err = c.sendAs(ctx, method, path, body, nil)
+	}
+	return r, err
+}
+
+// sendAs sends a request authenticated by cookie, or basic auth when cookie is nil
+func (c *Client) sendAs(ctx context.Context, method string, path string, body []byte, cookie *http.Cookie) (*resty.Response, error) {
+	req := c.rest.R().SetContext(ctx)
+	c.session.authenticate(req, cookie)
+	if body != nil {
+		req.SetBody(body)
+	}
+	r, err := req.Execute(method, path)
 	if err != nil {
+		return r, fmt.Errorf("infoblox %s %s failed: %w", method, path, err)
+	}
+	log.Printf("[DEBUG] %s %s returned %d", method, path, r.StatusCode())
+	if r.StatusCode() != 401 {
+		c.session.capture(r)
+	}
+	if r.IsError() {
+		return r, newWAPIError(method, path, r.StatusCode(), r.Body())
+	}
+	return r, nil
+}
+
+// IbGetTest sends a GET request to infoblox, checking the credentials and establishing the session later requests reuse
+func (c *Client) IbGetTest(ctx context.Context) error {
+	r, err := c.do(ctx, resty.MethodGet, "", nil)
+	var wapiErr *WAPIError
+	// the base url has no object so only credential and transport failures matter here
+	if err != nil && (IsUnauthorized(err) || !errors.As(err, &wapiErr)) {
 		log.Printf("Get request failed")
 		return err
 	}
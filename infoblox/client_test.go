@@ -0,0 +1,199 @@
+package infoblox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox/wapitest"
+)
+
+// newTestClient returns a client of a new stand-in WAPI, each configure function adjusting
+// the client configuration first
+func newTestClient(t *testing.T, configure ...func(*infoblox.Cfg)) (*infoblox.Client, *wapitest.Server) {
+	t.Helper()
+	s := wapitest.NewServer()
+	t.Cleanup(s.Close)
+	cfg := s.Cfg()
+	for _, f := range configure {
+		f(cfg)
+	}
+	c, err := infoblox.ClientInit(cfg)
+	if err != nil {
+		t.Fatalf("ClientInit: %s", err)
+	}
+	return c, s
+}
+
+func TestClientRecordLifecycle(t *testing.T) {
+	c, s := newTestClient(t)
+	ctx := context.Background()
+
+	if err := c.IbGetTest(ctx); err != nil {
+		t.Fatalf("IbGetTest: %s", err)
+	}
+
+	ref, err := c.IbCreateRecord(ctx, "a", []byte(`{"name":"host.example.com","ipv4addr":"10.0.0.1","comment":"first","view":"Internal"}`))
+	if err != nil {
+		t.Fatalf("IbCreateRecord: %s", err)
+	}
+	if rt, ok := infoblox.RefRecordType(ref); !ok || rt != "a" {
+		t.Fatalf("unexpected ref %q", ref)
+	}
+
+	i, err := c.IbReadRecordByRef(ctx, ref)
+	if err != nil {
+		t.Fatalf("IbReadRecordByRef: %s", err)
+	}
+	if i.Name != "host.example.com" || i.Ipv4addr != "10.0.0.1" || i.Comment != "first" || i.View != "Internal" {
+		t.Fatalf("unexpected record %+v", i)
+	}
+
+	newRef, err := c.IbUpdateRecord(ctx, ref, []byte(`{"name":"renamed.example.com","comment":"second"}`))
+	if err != nil {
+		t.Fatalf("IbUpdateRecord: %s", err)
+	}
+	if newRef == ref {
+		t.Fatalf("expected rename to change the ref")
+	}
+	i, err = c.IbFindRecord(ctx, "a", map[string]string{"name": "renamed.example.com", "view": "Internal"})
+	if err != nil {
+		t.Fatalf("IbFindRecord: %s", err)
+	}
+	if i.Ref != newRef || i.Comment != "second" {
+		t.Fatalf("unexpected record %+v", i)
+	}
+
+	if err := c.IbDeleteRecord(ctx, newRef); err != nil {
+		t.Fatalf("IbDeleteRecord: %s", err)
+	}
+	if len(s.Objects("record:a")) != 0 {
+		t.Fatalf("record still exists after delete")
+	}
+	if _, err := c.IbReadRecordByRef(ctx, newRef); !infoblox.IsNotFound(err) {
+		t.Fatalf("expected not found reading deleted record, got %v", err)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	c, s := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := c.IbReadRecord(ctx, "missing.example.com", "cname"); !infoblox.IsNotFound(err) {
+		t.Fatalf("expected not found for empty search, got %v", err)
+	}
+	if err := c.IbDeleteRecord(ctx, "record:cname/bm9uZQ:missing.example.com/default"); !infoblox.IsNotFound(err) {
+		t.Fatalf("expected not found deleting missing record, got %v", err)
+	}
+
+	body := []byte(`{"name":"dup.example.com","text":"v=spf1 -all"}`)
+	if _, err := c.IbCreateRecord(ctx, "txt", body); err != nil {
+		t.Fatalf("IbCreateRecord: %s", err)
+	}
+	_, err := c.IbCreateRecord(ctx, "txt", body)
+	if !infoblox.IsConflict(err) {
+		t.Fatalf("expected conflict creating duplicate, got %v", err)
+	}
+	if e, ok := err.(*infoblox.WAPIError); !ok || e.Text != "The record 'dup.example.com' already exists." {
+		t.Fatalf("expected infoblox error text to be kept, got %v", err)
+	}
+
+	cfg := s.Cfg()
+	cfg.Password = "wrong"
+	bad, err := infoblox.ClientInit(cfg)
+	if err != nil {
+		t.Fatalf("ClientInit: %s", err)
+	}
+	if err := bad.IbGetTest(ctx); !infoblox.IsUnauthorized(err) {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+}
+
+func TestClientHonoursContext(t *testing.T) {
+	c, s := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.IbCreateRecord(ctx, "a", []byte(`{"name":"host.example.com","ipv4addr":"10.0.0.1"}`))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled request, got %v", err)
+	}
+	if len(s.Objects("record:a")) != 0 {
+		t.Fatalf("cancelled create reached infoblox")
+	}
+}
+
+func TestClientExtAttrs(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	ref, err := c.IbCreateRecord(ctx, "a", []byte(`{"name":"host.example.com","ipv4addr":"10.0.0.1","extattrs":{"Owner":{"value":"team-a"},"Rack":{"value":12},"Site":{"value":["London","Paris"]}}}`))
+	if err != nil {
+		t.Fatalf("IbCreateRecord: %s", err)
+	}
+	ref, err = c.IbUpdateRecord(ctx, ref, []byte(`{"extattrs+":{"Owner":{"value":"team-b"}},"extattrs-":{"Rack":{}}}`))
+	if err != nil {
+		t.Fatalf("IbUpdateRecord: %s", err)
+	}
+
+	i, err := c.IbReadRecordByRef(ctx, ref)
+	if err != nil {
+		t.Fatalf("IbReadRecordByRef: %s", err)
+	}
+	got := map[string]string{}
+	for name, ea := range i.ExtAttrs {
+		got[name] = ea.String()
+	}
+	want := map[string]string{"Owner": "team-b", "Site": `["London","Paris"]`}
+	if len(got) != len(want) || got["Owner"] != want["Owner"] || got["Site"] != want["Site"] {
+		t.Fatalf("got extensible attributes %v, want %v", got, want)
+	}
+	for value, want := range map[interface{}]string{12.0: "12", 12345678.0: "12345678"} {
+		if s := (infoblox.ExtAttr{Value: value}).String(); s != want {
+			t.Fatalf("expected the number %v to read as %s, got %q", value, want, s)
+		}
+	}
+	if s := (infoblox.ExtAttr{Value: []interface{}{"a", 1.0}}).String(); s != `["a",1]` {
+		t.Fatalf("expected a list to keep the types of its values, got %q", s)
+	}
+}
+
+func TestClientExtAttrDefs(t *testing.T) {
+	c, s := newTestClient(t, func(cfg *infoblox.Cfg) { cfg.PageSize = 2 })
+	ctx := context.Background()
+	for _, def := range []map[string]interface{}{
+		{"name": "CostCentre", "type": "STRING"},
+		{"name": "Rack", "type": "INTEGER"},
+		{"name": "Site", "type": "STRING", "flags": "M"},
+	} {
+		if _, err := s.Create("extensibleattributedef", def); err != nil {
+			t.Fatalf("Create: %s", err)
+		}
+	}
+
+	// the definitions take two pages
+	defs, err := c.IbExtAttrDefs(ctx)
+	if err != nil {
+		t.Fatalf("IbExtAttrDefs: %s", err)
+	}
+	if len(defs) != 3 || defs["Rack"].Type != infoblox.ExtAttrTypeInteger || defs["CostCentre"].Multiple() || !defs["Site"].Multiple() {
+		t.Fatalf("unexpected definitions %v", defs)
+	}
+
+	// infoblox checks values against the type of their definition
+	for _, eas := range []string{
+		`{"CostCentre":{"value":1234}}`,
+		`{"Rack":{"value":"12"}}`,
+		`{"CostCentre":{"value":["1234"]}}`,
+	} {
+		body := `{"name":"host.example.com","ipv4addr":"10.0.0.1","extattrs":` + eas + `}`
+		if _, err := c.IbCreateRecord(ctx, "a", []byte(body)); err == nil {
+			t.Fatalf("expected extensible attributes %s to be rejected", eas)
+		}
+	}
+	body := `{"name":"host.example.com","ipv4addr":"10.0.0.1","extattrs":{"CostCentre":{"value":"1234"},"Rack":{"value":12},"Site":{"value":["London","Paris"]}}}`
+	if _, err := c.IbCreateRecord(ctx, "a", []byte(body)); err != nil {
+		t.Fatalf("IbCreateRecord: %s", err)
+	}
+}
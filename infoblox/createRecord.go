@@ -2,10 +2,11 @@
 package infoblox
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/go-resty/resty/v2"
 )
@@ -15,40 +16,28 @@ func init() {
 	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
 }
 
-// IbCreateRecord creates a record
-func IbCreateRecord(c *resty.Client, rcdType string, body []byte) (int, error) {
-	var url strings.Builder
-	switch rcdType {
-	case "a":
-		url.WriteString("/record:a")
-	case "txt":
-		url.WriteString("/record:txt")
-	case "cname":
-		url.WriteString("/record:cname")
-	default:
-		return 500, errors.New("Unsupported record type")
+// IbCreateRecord creates a record and returns the _ref infoblox assigned to it
+func (c *Client) IbCreateRecord(ctx context.Context, rcdType string, body []byte) (string, error) {
+	if _, ok := returnFields[rcdType]; !ok {
+		return "", errors.New("Unsupported record type")
 	}
-	log.Printf("IbCreateRecord endpoint: %s", url.String())
+	url := "/record:" + rcdType
+	log.Printf("IbCreateRecord endpoint: %s", url)
 	log.Printf("IbCreateRecord request body: %s", body)
 
-	r, err := c.R().SetBody(body).Post(url.String())
-	log.Printf("Response body: \n" + r.String())
-	sc := r.StatusCode()
-
-	if sc == 201 {
-		return sc, nil
-	} else if sc == 401 {
-		return 401, errors.New("Unauthorised: 401")
-	} else if sc == 404 {
-		log.Printf("Get request returned 404")
-		return 404, nil
-	} else if sc == 400 {
-		log.Printf("Bad request")
-		return 400, errors.New("Bad request: 400" + r.String())
-	} else if err != nil {
+	ref, err := c.write(ctx, BatchOp{Method: resty.MethodPost, Object: "record:" + rcdType, Data: body})
+	if err != nil {
 		log.Printf("Post request failed")
-		err = fmt.Errorf("Error: %s", err)
-		return 500, err
+		return "", err
+	}
+	return ref, nil
+}
+
+// decodeRef reads the bare json string infoblox returns from writes
+func decodeRef(body []byte) (string, error) {
+	var ref string
+	if err := json.Unmarshal(body, &ref); err != nil {
+		return "", fmt.Errorf("Error decoding record reference %q: %s", body, err)
 	}
-	return sc, nil
+	return ref, nil
 }
@@ -2,8 +2,7 @@
 package infoblox
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"log"
 
 	"github.com/go-resty/resty/v2"
@@ -14,27 +13,13 @@ func init() {
 	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
 }
 
-// IbDeleteARecord creates an a record
-func IbDeleteRecord(c *resty.Client, ref string) (int, error) {
+// IbDeleteRecord deletes a record
+func (c *Client) IbDeleteRecord(ctx context.Context, ref string) error {
 	log.Printf("IbDeleteRecord endpoint: /%s", ref)
 
-	r, err := c.R().Delete("/" + ref)
-	log.Printf("Response body: \n" + r.String())
-
-	if r.StatusCode() == 200 {
-		return r.StatusCode(), nil
-	} else if r.StatusCode() == 401 {
-		return 401, errors.New("Unauthorised: 401")
-	} else if r.StatusCode() == 404 {
-		log.Printf("Get request returned 404")
-		return 404, nil
-	} else if r.StatusCode() == 400 {
-		log.Printf("Bad request")
-		return 400, errors.New("Bad request: 400" + r.String())
-	} else if err != nil {
+	if _, err := c.write(ctx, BatchOp{Method: resty.MethodDelete, Object: ref}); err != nil {
 		log.Printf("Delete request failed")
-		err = fmt.Errorf("Error: %s", err)
-		return 500, err
+		return err
 	}
-	return r.StatusCode(), nil
+	return nil
 }
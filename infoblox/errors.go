@@ -0,0 +1,83 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WAPIError is returned when infoblox answers a request with an error status.
+// Error, code and text are parsed from the JSON body infoblox sends with every failure.
+type WAPIError struct {
+	StatusCode int    `json:"-"`
+	Method     string `json:"-"`
+	Path       string `json:"-"`
+	Err        string `json:"Error"`
+	Code       string `json:"code"`
+	Text       string `json:"text"`
+}
+
+// Error implements the error interface, preferring the human readable text infoblox supplies
+func (e *WAPIError) Error() string {
+	msg := e.Text
+	if msg == "" {
+		msg = e.Err
+	}
+	if msg == "" {
+		msg = "no error detail returned"
+	}
+	return fmt.Sprintf("infoblox %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, msg)
+}
+
+// newWAPIError builds a WAPIError from a failed response, falling back to the raw body if it isn't JSON
+func newWAPIError(method string, path string, statusCode int, body []byte) *WAPIError {
+	e := &WAPIError{}
+	if err := json.Unmarshal(body, e); err != nil {
+		e.Text = strings.TrimSpace(string(body))
+	}
+	e.StatusCode = statusCode
+	e.Method = method
+	e.Path = path
+	return e
+}
+
+// newNotFoundError mirrors the error infoblox returns for a missing reference,
+// used when a search returns no records so callers only have one not found case to handle
+func newNotFoundError(method string, path string, text string) *WAPIError {
+	return &WAPIError{
+		StatusCode: 404,
+		Method:     method,
+		Path:       path,
+		Code:       "Client.Ibap.Data.NotFound",
+		Text:       text,
+	}
+}
+
+// IsNotFound reports whether err means the requested record doesn't exist
+func IsNotFound(err error) bool {
+	var e *WAPIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.StatusCode == 404 || e.Code == "Client.Ibap.Data.NotFound"
+}
+
+// IsUnauthorized reports whether infoblox rejected the credentials
+func IsUnauthorized(err error) bool {
+	var e *WAPIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.StatusCode == 401
+}
+
+// IsConflict reports whether the request clashed with an existing record
+func IsConflict(err error) bool {
+	var e *WAPIError
+	if !errors.As(err, &e) {
+		return false
+	}
+	return e.StatusCode == 409 || e.Code == "Client.Ibap.Data.Conflict"
+}
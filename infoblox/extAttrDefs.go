@@ -0,0 +1,72 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func init() {
+	// remove date and time stamp from log output as the plugin SDK already adds its own
+	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
+}
+
+// ExtAttrTypeInteger is the type of extensible attributes holding whole numbers, sent as JSON numbers.
+// The values of every other type (STRING, EMAIL, URL, DATE and ENUM) are JSON strings.
+const ExtAttrTypeInteger = "INTEGER"
+
+// ExtAttrDef is the definition of an extensible attribute, which every attribute set on a record needs
+type ExtAttrDef struct {
+	Ref  string `json:"_ref"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Flags holds a letter for each option set on the definition, M allowing multiple values
+	Flags string `json:"flags"`
+}
+
+// Multiple reports whether the attribute can hold a list of values
+func (d ExtAttrDef) Multiple() bool {
+	return strings.Contains(d.Flags, "M")
+}
+
+// pagedExtAttrDefs is a page of definitions returned when _return_as_object is set
+type pagedExtAttrDefs struct {
+	Result     []ExtAttrDef `json:"result"`
+	NextPageID string       `json:"next_page_id"`
+}
+
+// IbExtAttrDefs returns every extensible attribute definition on the grid by name
+func (c *Client) IbExtAttrDefs(ctx context.Context) (map[string]ExtAttrDef, error) {
+	defs := map[string]ExtAttrDef{}
+	path := "/extensibleattributedef?_return_fields=" + url.QueryEscape("name,type,flags") +
+		"&_paging=1&_return_as_object=1&_max_results=" + strconv.Itoa(c.pageSize)
+	for path != "" {
+		log.Printf("IbExtAttrDefs endpoint: %s", path)
+		r, err := c.do(ctx, resty.MethodGet, path, nil)
+		if err != nil {
+			log.Printf("Get request failed")
+			return nil, err
+		}
+
+		var page pagedExtAttrDefs
+		if err := json.Unmarshal(r.Body(), &page); err != nil {
+			log.Printf("Error unmarshalling response into struct")
+			return nil, fmt.Errorf("Error decoding extensibleattributedef response: %s", err)
+		}
+		for _, d := range page.Result {
+			defs[d.Name] = d
+		}
+		path = ""
+		if page.NextPageID != "" {
+			path = "/extensibleattributedef?_page_id=" + url.QueryEscape(page.NextPageID)
+		}
+	}
+	return defs, nil
+}
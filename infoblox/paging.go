@@ -0,0 +1,102 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultPageSize is how many records are fetched per request when Cfg.PageSize isn't set
+const DefaultPageSize = 1000
+
+// RecordIterator pages through the records matching a query, fetching each page from infoblox as
+// the one before is used up. As with sql.Rows, call Next until it returns false then check Err.
+type RecordIterator struct {
+	client  *Client
+	rcdType string
+	query   *Query
+
+	page    []Result
+	pageID  string
+	fetched bool
+	current Result
+	err     error
+}
+
+// pagedResult is the object infoblox returns for each page when _return_as_object is set
+type pagedResult struct {
+	Result     []Result `json:"result"`
+	NextPageID string   `json:"next_page_id"`
+}
+
+// IbIterateRecords returns an iterator over every record matching the query, a nil query matches
+// every record of the type. Nothing is fetched until the first call to Next.
+func (c *Client) IbIterateRecords(rcdType string, q *Query) *RecordIterator {
+	it := &RecordIterator{client: c, rcdType: rcdType, query: q}
+	if _, ok := returnFields[rcdType]; !ok {
+		it.err = errors.New("Unsupported record type")
+	}
+	return it
+}
+
+// Next moves to the next record, fetching another page if needed. It returns false once every
+// record has been read or a request fails.
+func (it *RecordIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.err != nil || (it.fetched && it.pageID == "") {
+			return false
+		}
+		it.fetch(ctx)
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Record returns the record Next moved to
+func (it *RecordIterator) Record() Result {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *RecordIterator) Err() error {
+	return it.err
+}
+
+// fetch requests the first page, or the page after the last one fetched
+func (it *RecordIterator) fetch(ctx context.Context) {
+	path := "/record:" + it.rcdType + "?"
+	if it.fetched {
+		path += "_page_id=" + url.QueryEscape(it.pageID)
+	} else {
+		if args := it.query.Encode(); args != "" {
+			path += args + "&"
+		}
+		path += "_return_fields=" + url.QueryEscape(returnFields[it.rcdType]) +
+			"&_paging=1&_return_as_object=1&_max_results=" + strconv.Itoa(it.client.pageSize)
+	}
+	log.Printf("IbIterateRecords endpoint: %s", path)
+
+	r, err := it.client.do(ctx, resty.MethodGet, path, nil)
+	if err != nil {
+		log.Printf("Get request failed")
+		it.err = err
+		return
+	}
+	log.Printf("Response body: \n" + r.String())
+
+	var page pagedResult
+	if err := json.Unmarshal(r.Body(), &page); err != nil {
+		log.Printf("Error unmarshalling response into struct")
+		it.err = fmt.Errorf("Error decoding record:%s search response: %s", it.rcdType, err)
+		return
+	}
+	it.fetched = true
+	it.page, it.pageID = page.Result, page.NextPageID
+}
@@ -0,0 +1,105 @@
+package infoblox_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox/wapitest"
+)
+
+// newPagingTestClient returns a client fetching pageSize records at a time from a server holding
+// count A records, which refuses unpaged searches returning more than 5
+func newPagingTestClient(t *testing.T, pageSize int, count int) (*infoblox.Client, *wapitest.Server) {
+	t.Helper()
+	c, s := newTestClient(t, func(cfg *infoblox.Cfg) { cfg.PageSize = pageSize })
+	s.MaxResults = 5
+	for i := 0; i < count; i++ {
+		fields := map[string]interface{}{"name": fmt.Sprintf("host%02d.example.com", i), "ipv4addr": fmt.Sprintf("10.0.0.%d", i+1)}
+		if _, err := s.Create("record:a", fields); err != nil {
+			t.Fatalf("Create: %s", err)
+		}
+	}
+	return c, s
+}
+
+func TestPagingIterator(t *testing.T) {
+	c, s := newPagingTestClient(t, 10, 25)
+	ctx := context.Background()
+
+	it := c.IbIterateRecords("a", nil)
+	seen := map[string]bool{}
+	for it.Next(ctx) {
+		seen[it.Record().Name] = true
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iterating: %s", err)
+	}
+	if len(seen) != 25 {
+		t.Fatalf("expected 25 distinct records, got %d", len(seen))
+	}
+	if got := s.Requests(); got != 3 {
+		t.Fatalf("expected 3 pages, got %d requests", got)
+	}
+	if it.Next(ctx) {
+		t.Fatalf("expected a finished iterator to stay finished")
+	}
+}
+
+func TestPagingFetchesOnDemand(t *testing.T) {
+	c, s := newPagingTestClient(t, 10, 25)
+
+	it := c.IbIterateRecords("a", nil)
+	if s.Requests() != 0 {
+		t.Fatalf("expected nothing to be fetched before Next")
+	}
+	for i := 0; i < 10; i++ {
+		if !it.Next(context.Background()) {
+			t.Fatalf("Next: %v", it.Err())
+		}
+	}
+	if got := s.Requests(); got != 1 {
+		t.Fatalf("expected the first page only, got %d requests", got)
+	}
+}
+
+func TestPagingQueryReturnsAll(t *testing.T) {
+	c, _ := newPagingTestClient(t, 4, 12)
+
+	results, err := c.IbQueryRecords(context.Background(), "a", infoblox.NewQuery().Where("name", `^host0`, infoblox.Regex))
+	if err != nil {
+		t.Fatalf("IbQueryRecords: %s", err)
+	}
+	if len(results) != 10 {
+		t.Fatalf("expected every match beyond the server's result limit, got %d", len(results))
+	}
+
+	// an exact page boundary must not leave a dangling page
+	results, err = c.IbQueryRecords(context.Background(), "a", infoblox.NewQuery().Where("name", `^host0[0-7]`, infoblox.Regex))
+	if err != nil || len(results) != 8 {
+		t.Fatalf("expected 8 records, got %d: %v", len(results), err)
+	}
+}
+
+func TestPagingError(t *testing.T) {
+	c, s := newPagingTestClient(t, 10, 25)
+	ctx := context.Background()
+
+	it := c.IbIterateRecords("a", nil)
+	for i := 0; i < 10; i++ {
+		it.Next(ctx)
+	}
+	s.Fail(1, 400, "")
+	if it.Next(ctx) {
+		t.Fatalf("expected the failed page to stop the iteration")
+	}
+	if e, ok := it.Err().(*infoblox.WAPIError); !ok || e.StatusCode != 400 {
+		t.Fatalf("expected the page error, got %v", it.Err())
+	}
+
+	it = c.IbIterateRecords("unknown", nil)
+	if it.Next(ctx) || it.Err() == nil {
+		t.Fatalf("expected an unsupported record type to fail")
+	}
+}
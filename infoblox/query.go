@@ -0,0 +1,117 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Modifier changes how a search argument is compared, modifiers can be combined such as Not with Regex
+type Modifier string
+
+// Search modifiers supported by the WAPI
+const (
+	// Regex matches the value as a regular expression
+	Regex Modifier = "~"
+	// CaseInsensitive matches the value ignoring case
+	CaseInsensitive Modifier = ":"
+	// Not inverts the match
+	Not Modifier = "!"
+	// LessOrEqual matches fields up to and including the value
+	LessOrEqual Modifier = "<"
+	// GreaterOrEqual matches fields from the value up
+	GreaterOrEqual Modifier = ">"
+)
+
+// modifierOrder is the order WAPI expects modifiers in after the field name
+var modifierOrder = []Modifier{Not, CaseInsensitive, Regex, LessOrEqual, GreaterOrEqual}
+
+// Query holds the search arguments of a WAPI GET, each argument must match for an object to be returned.
+// The zero value matches everything.
+type Query struct {
+	args []queryArg
+}
+
+type queryArg struct {
+	key   string
+	value string
+}
+
+// NewQuery returns an empty Query
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// ExactQuery returns a Query matching every field exactly, in field name order
+func ExactQuery(fields map[string]string) *Query {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	q := NewQuery()
+	for _, k := range keys {
+		q.Where(k, fields[k])
+	}
+	return q
+}
+
+// Where adds a search on field, compared exactly unless modifiers are given
+func (q *Query) Where(field string, value string, mods ...Modifier) *Query {
+	q.args = append(q.args, queryArg{key: field + modifierSuffix(mods), value: value})
+	return q
+}
+
+// ExtAttr adds a search on the extensible attribute name
+func (q *Query) ExtAttr(name string, value string, mods ...Modifier) *Query {
+	return q.Where("*"+name, value, mods...)
+}
+
+// View limits the search to a DNS view
+func (q *Query) View(view string) *Query {
+	return q.Where("view", view)
+}
+
+// Zone limits the search to a DNS zone
+func (q *Query) Zone(zone string) *Query {
+	return q.Where("zone", zone)
+}
+
+// Encode returns the arguments URL encoded in the order they were added
+func (q *Query) Encode() string {
+	if q == nil {
+		return ""
+	}
+	parts := make([]string, len(q.args))
+	for i, a := range q.args {
+		parts[i] = url.QueryEscape(a.key) + "=" + url.QueryEscape(a.value)
+	}
+	return strings.Join(parts, "&")
+}
+
+// String describes the search for errors and logs
+func (q *Query) String() string {
+	if q == nil || len(q.args) == 0 {
+		return "any"
+	}
+	parts := make([]string, len(q.args))
+	for i, a := range q.args {
+		parts[i] = a.key + "=" + a.value
+	}
+	return strings.Join(parts, " ")
+}
+
+// modifierSuffix orders and deduplicates modifiers as WAPI expects them between the field and =
+func modifierSuffix(mods []Modifier) string {
+	var b strings.Builder
+	for _, m := range modifierOrder {
+		for _, given := range mods {
+			if given == m {
+				b.WriteString(string(m))
+				break
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package infoblox_test
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+func TestQueryEncode(t *testing.T) {
+	tests := map[string]struct {
+		q    *infoblox.Query
+		want string
+	}{
+		"empty": {infoblox.NewQuery(), ""},
+		"nil":   {nil, ""},
+		"exact": {
+			infoblox.ExactQuery(map[string]string{"view": "Internal", "name": "host.example.com"}),
+			"name=host.example.com&view=Internal",
+		},
+		"escaped": {
+			infoblox.NewQuery().Where("comment", "a b&c=d"),
+			"comment=a+b%26c%3Dd",
+		},
+		"modifiers": {
+			infoblox.NewQuery().
+				Where("name", "^host", infoblox.Regex, infoblox.Not).
+				Where("comment", "Test", infoblox.CaseInsensitive).
+				Where("ttl", "300", infoblox.GreaterOrEqual),
+			"name%21~=%5Ehost&comment%3A=Test&ttl%3E=300",
+		},
+		"extensible attribute": {
+			infoblox.NewQuery().ExtAttr("Site", "London").View("Internal").Zone("example.com"),
+			"%2ASite=London&view=Internal&zone=example.com",
+		},
+	}
+	for name, tc := range tests {
+		if got := tc.q.Encode(); got != tc.want {
+			t.Errorf("%s: got %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestQueryRecords(t *testing.T) {
+	c, s := newTestClient(t)
+	ctx := context.Background()
+	for _, rec := range []map[string]interface{}{
+		{"name": "web1.example.com", "ipv4addr": "10.0.0.1", "view": "Internal", "comment": "Web server"},
+		{"name": "web2.example.com", "ipv4addr": "10.0.0.2", "view": "Internal", "comment": "web server"},
+		{"name": "db1.example.com", "ipv4addr": "10.0.0.3", "view": "Internal", "comment": "Database"},
+		{"name": "web1.example.org", "ipv4addr": "10.0.1.1", "view": "External", "comment": "Web server"},
+	} {
+		if _, err := s.Create("record:a", rec); err != nil {
+			t.Fatalf("Create: %s", err)
+		}
+	}
+
+	tests := map[string]struct {
+		q    *infoblox.Query
+		want string
+	}{
+		"everything":       {nil, "db1.example.com web1.example.com web1.example.org web2.example.com"},
+		"exact":            {infoblox.NewQuery().Where("comment", "Web server"), "web1.example.com web1.example.org"},
+		"case insensitive": {infoblox.NewQuery().Where("comment", "WEB SERVER", infoblox.CaseInsensitive), "web1.example.com web1.example.org web2.example.com"},
+		"regex":            {infoblox.NewQuery().Where("name", `^web\d\.`, infoblox.Regex).View("Internal"), "web1.example.com web2.example.com"},
+		"negated regex":    {infoblox.NewQuery().Where("name", "^web", infoblox.Regex, infoblox.Not), "db1.example.com"},
+		"negated":          {infoblox.NewQuery().Where("view", "Internal", infoblox.Not), "web1.example.org"},
+		"range":            {infoblox.NewQuery().Where("ipv4addr", "10.0.0.2", infoblox.GreaterOrEqual).Where("ipv4addr", "10.0.0.3", infoblox.LessOrEqual), "db1.example.com web2.example.com"},
+		"zone":             {infoblox.NewQuery().Zone("example.org"), "web1.example.org"},
+		"no match":         {infoblox.NewQuery().Where("name", "missing.example.com"), ""},
+	}
+	for name, tc := range tests {
+		results, err := c.IbQueryRecords(ctx, "a", tc.q)
+		if err != nil {
+			t.Fatalf("%s: IbQueryRecords: %s", name, err)
+		}
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Name)
+		}
+		sort.Strings(names)
+		if got := strings.Join(names, " "); got != tc.want {
+			t.Errorf("%s: got %q, want %q", name, got, tc.want)
+		}
+	}
+
+	if _, err := c.IbQueryRecords(ctx, "a", infoblox.NewQuery().Where("name", "(", infoblox.Regex)); err == nil {
+		t.Fatalf("expected an invalid regex to be rejected")
+	}
+}
+
+func TestQueryExtensibleAttributes(t *testing.T) {
+	c, s := newTestClient(t)
+	for name, site := range map[string]interface{}{
+		"ldn.example.com":  "London",
+		"both.example.com": []interface{}{"London", "Paris"},
+		"par.example.com":  "Paris",
+	} {
+		fields := map[string]interface{}{
+			"name":     name,
+			"ipv4addr": "10.0.0.1",
+			"extattrs": map[string]interface{}{"Site": map[string]interface{}{"value": site}},
+		}
+		if _, err := s.Create("record:a", fields); err != nil {
+			t.Fatalf("Create: %s", err)
+		}
+	}
+
+	results, err := c.IbQueryRecords(context.Background(), "a", infoblox.NewQuery().ExtAttr("Site", "London"))
+	if err != nil {
+		t.Fatalf("IbQueryRecords: %s", err)
+	}
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Name)
+	}
+	sort.Strings(names)
+	if got := strings.Join(names, " "); got != "both.example.com ldn.example.com" {
+		t.Fatalf("unexpected records %q", got)
+	}
+}
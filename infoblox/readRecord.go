@@ -2,8 +2,8 @@
 package infoblox
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -13,13 +13,78 @@ import (
 
 // Result contains the json fields from a Get request
 type Result struct {
-	Ref       string `json:"_ref"`
-	Comment   string `json:"comment"`
-	Text      string `json:"text"`
-	Ipv4addr  string `json:"ipv4addr"`
-	Name      string `json:"name"`
-	Canonical string `json:"canonical"`
-	View      string `json:"view"`
+	Ref       string             `json:"_ref"`
+	Comment   string             `json:"comment"`
+	Text      string             `json:"text"`
+	Ipv4addr  string             `json:"ipv4addr"`
+	Ipv6addr  string             `json:"ipv6addr"`
+	Name      string             `json:"name"`
+	Canonical string             `json:"canonical"`
+	Ptrdname  string             `json:"ptrdname"`
+	View      string             `json:"view"`
+	ExtAttrs  map[string]ExtAttr `json:"extattrs"`
+	// TTL only applies when UseTTL is set, otherwise the record inherits the zone TTL
+	TTL     int    `json:"ttl"`
+	UseTTL  bool   `json:"use_ttl"`
+	Disable bool   `json:"disable"`
+	Creator string `json:"creator"`
+	// host records only
+	Ipv4addrs       []HostAddress `json:"ipv4addrs"`
+	Ipv6addrs       []HostAddress `json:"ipv6addrs"`
+	ConfigureForDNS bool          `json:"configure_for_dns"`
+	Aliases         []string      `json:"aliases"`
+	// MX records only
+	MailExchanger string `json:"mail_exchanger"`
+	Preference    int    `json:"preference"`
+	// SRV records only
+	Priority int    `json:"priority"`
+	Weight   int    `json:"weight"`
+	Port     int    `json:"port"`
+	Target   string `json:"target"`
+}
+
+// HostAddress is one of the addresses of a host record, an IPv4 address with the MAC of the
+// interface or an IPv6 address with the DUID of the DHCPv6 client
+type HostAddress struct {
+	Ref              string `json:"_ref,omitempty"`
+	Ipv4addr         string `json:"ipv4addr,omitempty"`
+	Ipv6addr         string `json:"ipv6addr,omitempty"`
+	Mac              string `json:"mac,omitempty"`
+	Duid             string `json:"duid,omitempty"`
+	ConfigureForDHCP bool   `json:"configure_for_dhcp"`
+}
+
+// ExtAttr is the value of an extensible attribute, a string or number or a list of them for
+// attributes allowing multiple values
+type ExtAttr struct {
+	Value interface{} `json:"value"`
+}
+
+// String returns the value as text, a number or the values of a multi-value attribute in JSON
+func (ea ExtAttr) String() string {
+	if s, ok := ea.Value.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(ea.Value)
+	if err != nil {
+		return fmt.Sprint(ea.Value)
+	}
+	return string(b)
+}
+
+// commonReturnFields are requested for every record type, all but host records also have a creator
+const commonReturnFields = "name,view,comment,disable,ttl,use_ttl,extattrs"
+
+// returnFields lists the fields requested for each supported record type
+var returnFields = map[string]string{
+	"a":     "ipv4addr,creator," + commonReturnFields,
+	"txt":   "text,creator," + commonReturnFields,
+	"cname": "canonical,creator," + commonReturnFields,
+	"aaaa":  "ipv6addr,creator," + commonReturnFields,
+	"ptr":   "ptrdname,ipv4addr,ipv6addr,creator," + commonReturnFields,
+	"mx":    "mail_exchanger,preference,creator," + commonReturnFields,
+	"srv":   "priority,weight,port,target,creator," + commonReturnFields,
+	"host":  "ipv4addrs,ipv6addrs,configure_for_dns,aliases," + commonReturnFields,
 }
 
 func init() {
@@ -27,60 +92,84 @@ func init() {
 	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
 }
 
-// IbReadRecord returns data about a record
-func IbReadRecord(c *resty.Client, name string, rcdType string) (int, Result, error) {
-	var url strings.Builder
-	switch rcdType {
-	case "a":
-		url.WriteString("/record:a?name=")
-		url.WriteString(name)
-		url.WriteString("&_return_fields=ipv4addr,name,view,comment")
-	case "txt":
-		url.WriteString("/record:txt?name=")
-		url.WriteString(name)
-		url.WriteString("&_return_fields=name,view,text")
-	case "cname":
-		url.WriteString("/record:cname?name=")
-		url.WriteString(name)
-		url.WriteString("&_return_fields=name,view,comment,canonical")
-	default:
-		return 500, Result{}, errors.New("Unsupported record type")
+// RefRecordType returns the record type encoded in a WAPI _ref, e.g. "a" for "record:a/ZG5z...:name/view"
+func RefRecordType(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, "record:") {
+		return "", false
 	}
-	log.Printf("IbReadRecord endpoint: %s", url.String())
-
-	r, err := c.R().Get(url.String())
-	log.Printf("Response body: \n" + r.String())
+	slash := strings.Index(ref, "/")
+	if slash == -1 {
+		return "", false
+	}
+	rcdType := ref[len("record:"):slash]
+	if _, ok := returnFields[rcdType]; !ok {
+		return "", false
+	}
+	return rcdType, true
+}
 
-	// requires struct array as response returns a list of json[]
-	log.Printf("Initialise Result struct")
-	var result []Result
+// IbReadRecord returns data about the first record found with name, a search without matches is reported as a not found WAPIError
+func (c *Client) IbReadRecord(ctx context.Context, name string, rcdType string) (Result, error) {
+	return c.IbFindRecord(ctx, rcdType, map[string]string{"name": name})
+}
 
-	log.Printf("Populating Result struct...")
-	err = json.Unmarshal(r.Body(), &result)
+// IbFindRecord returns the first record matching every field, a search without matches is reported as a not found WAPIError
+func (c *Client) IbFindRecord(ctx context.Context, rcdType string, fields map[string]string) (Result, error) {
+	result, err := c.IbSearchRecords(ctx, rcdType, fields)
 	if err != nil {
-		log.Printf("Error unmarshalling response into struct")
-		return 500, Result{}, err
+		return Result{}, err
 	}
-
-	if r.String() == "[]" {
+	if len(result) == 0 {
 		log.Printf("Empty response body")
-		return 404, Result{}, nil
+		return Result{}, newNotFoundError(resty.MethodGet, "/record:"+rcdType, fmt.Sprintf("record:%s matching %v not found", rcdType, fields))
+	}
+	if len(result) > 1 {
+		log.Printf("[WARN] %d record:%s match %v, using %s", len(result), rcdType, fields, result[0].Ref)
 	}
+	return result[0], nil
+}
 
-	if r.StatusCode() == 401 {
-		return 401, Result{}, errors.New("Unauthorised: 401")
-	} else if r.StatusCode() == 404 {
-		log.Printf("Get request returned 404")
-		return 404, Result{}, nil
-	} else if err != nil {
-		log.Printf("Get request failed")
-		err = fmt.Errorf("Error: %s", err)
-		return 500, Result{}, err
+// IbSearchRecords returns every record matching all the given fields exactly
+func (c *Client) IbSearchRecords(ctx context.Context, rcdType string, fields map[string]string) ([]Result, error) {
+	return c.IbQueryRecords(ctx, rcdType, ExactQuery(fields))
+}
+
+// IbQueryRecords returns every record matching the query, a nil query matches every record of the type.
+// Results are fetched a page at a time so large result sets aren't cut short.
+func (c *Client) IbQueryRecords(ctx context.Context, rcdType string, q *Query) ([]Result, error) {
+	result := []Result{}
+	it := c.IbIterateRecords(rcdType, q)
+	for it.Next(ctx) {
+		result = append(result, it.Record())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
-	log.Println("Struct:")
-	log.Println(result)
+	return result, nil
+}
 
-	log.Println("Status code: ", r.StatusCode())
+// IbReadRecordByRef returns data about the record with the given _ref
+func (c *Client) IbReadRecordByRef(ctx context.Context, ref string) (Result, error) {
+	rcdType, ok := RefRecordType(ref)
+	if !ok {
+		// anything that isn't a record reference can't exist in infoblox
+		return Result{}, newNotFoundError(resty.MethodGet, "/"+ref, fmt.Sprintf("%q is not a record reference", ref))
+	}
+	path := "/" + ref + "?_return_fields=" + returnFields[rcdType]
+	log.Printf("IbReadRecordByRef endpoint: %s", path)
 
-	return r.StatusCode(), result[0], nil
+	r, err := c.do(ctx, resty.MethodGet, path, nil)
+	if err != nil {
+		log.Printf("Get request failed")
+		return Result{}, err
+	}
+	log.Printf("Response body: \n" + r.String())
+
+	var result Result
+	err = json.Unmarshal(r.Body(), &result)
+	if err != nil {
+		log.Printf("Error unmarshalling response into struct")
+		return Result{}, fmt.Errorf("Error decoding %s response: %s", ref, err)
+	}
+	return result, nil
 }
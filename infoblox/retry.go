@@ -0,0 +1,126 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests failing with transient errors are retried.
+// A zero value field takes its value from DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, 1 disables retries
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry, doubling for each one after
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponential backoff, Retry-After from infoblox can exceed it
+	MaxBackoff time.Duration
+	// RetryableStatusCodes are the response codes treated as transient
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy retries the errors seen while a grid master fails over
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          4,
+		MinBackoff:           time.Second,
+		MaxBackoff:           30 * time.Second,
+		RetryableStatusCodes: []int{429, 502, 503, 504},
+	}
+}
+
+// withDefaults fills unset fields from DefaultRetryPolicy
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	d := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+	if p.MinBackoff <= 0 {
+		p.MinBackoff = d.MinBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = d.MaxBackoff
+	}
+	if p.MaxBackoff < p.MinBackoff {
+		p.MaxBackoff = p.MinBackoff
+	}
+	if len(p.RetryableStatusCodes) == 0 {
+		p.RetryableStatusCodes = d.RetryableStatusCodes
+	}
+	return p
+}
+
+// retryable decides whether a failed attempt can safely be sent again.
+// GET, PUT and DELETE are idempotent so any transport error or retryable status is retried.
+// A POST is only retried when infoblox can't have acted on it: the connection was never
+// established, or infoblox explicitly turned it away with 429 or 503.
+func (p RetryPolicy) retryable(method string, statusCode int, err error) bool {
+	if err != nil {
+		if method != http.MethodPost {
+			return true
+		}
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+	if method == http.MethodPost && statusCode != 429 && statusCode != 503 {
+		return false
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait before the given retry, exponential with jitter so parallel
+// resources don't retry in lockstep. A Retry-After header from infoblox takes precedence.
+func (p RetryPolicy) backoff(retry int, retryAfter string) time.Duration {
+	if wait, ok := parseRetryAfter(retryAfter); ok {
+		return wait
+	}
+	wait := p.MinBackoff
+	for i := 1; i < retry && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	// full jitter over the upper half of the window
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		wait := time.Until(t)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleep waits for d unless ctx finishes first
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
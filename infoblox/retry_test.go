@@ -0,0 +1,93 @@
+package infoblox_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+// fastRetry retries three times without waiting long between attempts
+func fastRetry(cfg *infoblox.Cfg) {
+	cfg.Retry = infoblox.RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	}
+}
+
+func TestRetryIdempotentRequests(t *testing.T) {
+	c, s := newTestClient(t, fastRetry)
+	ctx := context.Background()
+
+	s.Fail(1, 503, "")
+	s.Drop(1)
+	if _, err := c.IbSearchRecords(ctx, "a", map[string]string{"name": "host.example.com"}); err != nil {
+		t.Fatalf("expected search to succeed after retries, got %s", err)
+	}
+	if got := s.Requests(); got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+
+	s.Fail(3, 502, "")
+	_, err := c.IbSearchRecords(ctx, "a", map[string]string{"name": "host.example.com"})
+	if e, ok := err.(*infoblox.WAPIError); !ok || e.StatusCode != 502 {
+		t.Fatalf("expected 502 once attempts run out, got %v", err)
+	}
+	if got := s.Requests(); got != 6 {
+		t.Fatalf("expected 3 more requests, got %d", got)
+	}
+}
+
+func TestRetryCreateOnlyWhenNotProcessed(t *testing.T) {
+	c, s := newTestClient(t, fastRetry)
+	ctx := context.Background()
+	body := []byte(`{"name":"host.example.com","ipv4addr":"10.0.0.1"}`)
+
+	// infoblox turned the request away so it is safe to send again
+	s.Fail(1, 503, "0")
+	if _, err := c.IbCreateRecord(ctx, "a", body); err != nil {
+		t.Fatalf("expected create to succeed after 503, got %s", err)
+	}
+	if got := s.Requests(); got != 2 {
+		t.Fatalf("expected 2 requests, got %d", got)
+	}
+
+	// a gateway error may hide a create that went through
+	s.Fail(1, 502, "")
+	if _, err := c.IbCreateRecord(ctx, "a", []byte(`{"name":"other.example.com","ipv4addr":"10.0.0.2"}`)); err == nil {
+		t.Fatalf("expected create to fail on 502")
+	}
+	if got := s.Requests(); got != 3 {
+		t.Fatalf("expected create not to be retried after 502, got %d requests", got)
+	}
+
+	// as may a connection dropped after the request was sent
+	s.Drop(1)
+	if _, err := c.IbCreateRecord(ctx, "a", []byte(`{"name":"other.example.com","ipv4addr":"10.0.0.2"}`)); err == nil {
+		t.Fatalf("expected create to fail on dropped connection")
+	}
+	if got := s.Requests(); got != 4 {
+		t.Fatalf("expected create not to be retried after dropped connection, got %d requests", got)
+	}
+}
+
+func TestRetryStopsAtDeadline(t *testing.T) {
+	c, s := newTestClient(t, fastRetry)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Retry-After beyond the deadline means waiting can't help
+	s.Fail(1, 503, "60")
+	start := time.Now()
+	if _, err := c.IbSearchRecords(ctx, "a", map[string]string{"name": "host.example.com"}); err == nil {
+		t.Fatalf("expected search to fail")
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("retry waited past the deadline")
+	}
+	if got := s.Requests(); got != 1 {
+		t.Fatalf("expected a single request, got %d", got)
+	}
+}
@@ -0,0 +1,32 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ReverseName returns the name of the PTR record for an IPv4 or IPv6 address, in the
+// in-addr.arpa or ip6.arpa zone, e.g. "9.13.168.192.in-addr.arpa" for 192.168.13.9
+func ReverseName(addr string) (string, error) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return "", fmt.Errorf("Invalid IP address %q", addr)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", v4[3], v4[2], v4[1], v4[0]), nil
+	}
+
+	// one label per nibble, least significant first
+	const hex = "0123456789abcdef"
+	var b strings.Builder
+	for i := len(ip) - 1; i >= 0; i-- {
+		b.WriteByte(hex[ip[i]&0xf])
+		b.WriteByte('.')
+		b.WriteByte(hex[ip[i]>>4])
+		b.WriteByte('.')
+	}
+	b.WriteString("ip6.arpa")
+	return b.String(), nil
+}
@@ -0,0 +1,33 @@
+package infoblox_test
+
+import (
+	"testing"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+func TestReverseName(t *testing.T) {
+	tests := map[string]string{
+		"192.168.13.9":             "9.13.168.192.in-addr.arpa",
+		"10.0.0.1":                 "1.0.0.10.in-addr.arpa",
+		"::ffff:10.0.0.1":          "1.0.0.10.in-addr.arpa",
+		"2001:db8::1":              "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+		"2001:0DB8:0:0:0:0:0:abcd": "d.c.b.a.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+	}
+	for addr, want := range tests {
+		got, err := infoblox.ReverseName(addr)
+		if err != nil {
+			t.Errorf("%s: %s", addr, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: got %q, want %q", addr, got, want)
+		}
+	}
+
+	for _, addr := range []string{"", "host.example.com", "10.0.0.256", "2001:db8::1/64"} {
+		if _, err := infoblox.ReverseName(addr); err == nil {
+			t.Errorf("%q: expected an error", addr)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// sessionCookie is the cookie infoblox issues once a request has authenticated
+const sessionCookie = "ibapauth"
+
+// session reuses the ibapauth cookie from the first authenticated response for later requests.
+// Infoblox audits every basic auth request as a separate login, the cookie avoids that.
+type session struct {
+	username string
+	password string
+
+	mu     sync.Mutex
+	cookie *http.Cookie
+}
+
+// current returns the session cookie, or nil if there is no live session
+func (s *session) current() *http.Cookie {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cookie != nil && !s.cookie.Expires.IsZero() && time.Now().After(s.cookie.Expires) {
+		s.cookie = nil
+	}
+	return s.cookie
+}
+
+// authenticate adds the session cookie to req, or basic auth credentials when cookie is nil
+func (s *session) authenticate(req *resty.Request, cookie *http.Cookie) {
+	if cookie != nil {
+		req.SetCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+		return
+	}
+	req.SetBasicAuth(s.username, s.password)
+}
+
+// capture keeps the session cookie from a response if infoblox issued one
+func (s *session) capture(r *resty.Response) {
+	for _, c := range r.Cookies() {
+		if c.Name == sessionCookie && c.Value != "" {
+			s.mu.Lock()
+			if s.cookie == nil || s.cookie.Value != c.Value {
+				log.Printf("[DEBUG] infoblox session established")
+			}
+			s.cookie = c
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// expire drops cookie after infoblox rejected it, unless another request already replaced it
+func (s *session) expire(cookie *http.Cookie) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cookie != nil && s.cookie.Value == cookie.Value {
+		log.Printf("[DEBUG] infoblox session expired, logging in again")
+		s.cookie = nil
+	}
+}
@@ -0,0 +1,49 @@
+package infoblox_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+func TestSessionReuse(t *testing.T) {
+	c, s := newTestClient(t)
+	ctx := context.Background()
+
+	if err := c.IbGetTest(ctx); err != nil {
+		t.Fatalf("IbGetTest: %s", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := c.IbSearchRecords(ctx, "a", map[string]string{"name": "host.example.com"}); err != nil {
+			t.Fatalf("IbSearchRecords: %s", err)
+		}
+	}
+	if got := s.Logins(); got != 1 {
+		t.Fatalf("expected the session from the first request to be reused, got %d logins", got)
+	}
+
+	s.ExpireSessions()
+	if _, err := c.IbCreateRecord(ctx, "a", []byte(`{"name":"host.example.com","ipv4addr":"10.0.0.1"}`)); err != nil {
+		t.Fatalf("expected create to log in again after the session expired, got %s", err)
+	}
+	if got := len(s.Objects("record:a")); got != 1 {
+		t.Fatalf("expected one record, got %d", got)
+	}
+	if got := s.Logins(); got != 2 {
+		t.Fatalf("expected a single login after expiry, got %d logins", got)
+	}
+}
+
+func TestSessionBadCredentials(t *testing.T) {
+	c, s := newTestClient(t, func(cfg *infoblox.Cfg) { cfg.Password = "wrong" })
+
+	for i := 0; i < 2; i++ {
+		if err := c.IbGetTest(context.Background()); !infoblox.IsUnauthorized(err) {
+			t.Fatalf("expected unauthorized, got %v", err)
+		}
+	}
+	if got := s.Requests(); got != 2 {
+		t.Fatalf("expected no extra login attempts without a session, got %d requests", got)
+	}
+}
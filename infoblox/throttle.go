@@ -0,0 +1,53 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"context"
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// throttle caps how many requests are in flight and how fast new ones start. One throttle is
+// shared by everything using a Client, so terraform parallelism can't overwhelm the appliance.
+type throttle struct {
+	slots   chan struct{}
+	limiter *rate.Limiter
+}
+
+// newThrottle builds a throttle, a limit of zero or less leaves that dimension unlimited
+func newThrottle(maxConcurrent int, perSecond float64) *throttle {
+	t := &throttle{}
+	if maxConcurrent > 0 {
+		t.slots = make(chan struct{}, maxConcurrent)
+	}
+	if perSecond > 0 {
+		t.limiter = rate.NewLimiter(rate.Limit(perSecond), int(math.Ceil(perSecond)))
+	}
+	return t
+}
+
+// acquire blocks until a request may be sent or ctx is done, release must follow a nil return
+func (t *throttle) acquire(ctx context.Context) error {
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			t.release()
+			return err
+		}
+	}
+	return nil
+}
+
+// release frees the slot taken by acquire
+func (t *throttle) release() {
+	if t.slots != nil {
+		<-t.slots
+	}
+}
@@ -0,0 +1,53 @@
+package infoblox_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+// searchConcurrently runs n searches at once, as terraform does with parallel resources
+func searchConcurrently(t *testing.T, c *infoblox.Client, n int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.IbSearchRecords(context.Background(), "a", map[string]string{"name": "host.example.com"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("IbSearchRecords: %s", err)
+		}
+	}
+}
+
+func TestThrottleConcurrency(t *testing.T) {
+	c, s := newTestClient(t, func(cfg *infoblox.Cfg) { cfg.MaxConcurrentRequests = 2 })
+	s.Latency = 20 * time.Millisecond
+
+	searchConcurrently(t, c, 10)
+	if got := s.MaxInFlight(); got > 2 {
+		t.Fatalf("expected at most 2 requests in flight, got %d", got)
+	}
+}
+
+func TestThrottleRate(t *testing.T) {
+	c, _ := newTestClient(t, func(cfg *infoblox.Cfg) { cfg.RequestsPerSecond = 50 })
+
+	// the first 50 use the burst, the next 10 have to wait for tokens
+	start := time.Now()
+	searchConcurrently(t, c, 60)
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected requests to be rate limited, 60 took %s", elapsed)
+	}
+}
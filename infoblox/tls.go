@@ -0,0 +1,85 @@
+// Package infoblox provides REST actions against an infoblox WAPI
+package infoblox
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// tlsVersions maps the versions accepted for Cfg.MinTLSVersion
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSVersions lists the values accepted for Cfg.MinTLSVersion
+func TLSVersions() []string {
+	return []string{"1.0", "1.1", "1.2", "1.3"}
+}
+
+// tlsConfig builds the TLS settings for talking to infoblox from the config
+func tlsConfig(c *Cfg) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: !c.TLSVerify}
+
+	if c.MinTLSVersion != "" {
+		v, ok := tlsVersions[c.MinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("Invalid MinTLSVersion setting %q, expected one of %s", c.MinTLSVersion, strings.Join(TLSVersions(), ", "))
+		}
+		cfg.MinVersion = v
+	}
+
+	if c.CACertFile != "" || c.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if c.CACertFile != "" {
+			pem, err := ioutil.ReadFile(c.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("Error reading CA certificate file: %s", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("No certificates found in CA certificate file %s", c.CACertFile)
+			}
+		}
+		if c.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(c.CACertPEM)) {
+			return nil, fmt.Errorf("No certificates found in CA certificate PEM")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, fmt.Errorf("ClientCert and ClientKey must be set together")
+		}
+		certPEM, err := pemOrFile(c.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading client certificate: %s", err)
+		}
+		keyPEM, err := pemOrFile(c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading client key: %s", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid client certificate: %s", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// pemOrFile returns v itself if it holds PEM data, otherwise the contents of the file it names
+func pemOrFile(v string) ([]byte, error) {
+	if strings.Contains(v, "-----BEGIN") {
+		return []byte(v), nil
+	}
+	return ioutil.ReadFile(v)
+}
@@ -0,0 +1,211 @@
+package infoblox_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox/wapitest"
+)
+
+// testCert is a generated certificate with its key, in both PEM and parsed form
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	pair, err := tls.X509KeyPair([]byte(c.certPEM), []byte(c.keyPEM))
+	if err != nil {
+		t.Fatalf("X509KeyPair: %s", err)
+	}
+	return pair
+}
+
+// newTestCert generates a certificate signed by parent, or a self signed CA when parent is nil
+func newTestCert(t *testing.T, cn string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %s", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		tmpl.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey: %s", err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+// newTLSTestServer starts a fake WAPI presenting a certificate from ca, applying
+// configure to its TLS settings first
+func newTLSTestServer(t *testing.T, ca *testCert, configure func(*tls.Config)) *wapitest.Server {
+	t.Helper()
+	s := wapitest.NewUnstartedServer()
+	s.TLS = &tls.Config{
+		Certificates: []tls.Certificate{newTestCert(t, "infoblox", ca, x509.ExtKeyUsageServerAuth).tlsCertificate(t)},
+	}
+	if configure != nil {
+		configure(s.TLS)
+	}
+	s.StartTLS()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// testTLSConnect reports the outcome of a request made with cfg, handshake failures aren't retried
+func testTLSConnect(t *testing.T, cfg *infoblox.Cfg) error {
+	t.Helper()
+	cfg.Retry = infoblox.RetryPolicy{MaxAttempts: 1}
+	c, err := infoblox.ClientInit(cfg)
+	if err != nil {
+		t.Fatalf("ClientInit: %s", err)
+	}
+	return c.IbGetTest(context.Background())
+}
+
+func TestTLSCustomCA(t *testing.T) {
+	ca := newTestCert(t, "test ca", nil, 0)
+	s := newTLSTestServer(t, ca, nil)
+
+	cfg := s.Cfg()
+	cfg.TLSVerify = true
+	if err := testTLSConnect(t, cfg); err == nil {
+		t.Fatalf("expected a certificate from an unknown CA to be rejected")
+	}
+
+	cfg.CACertPEM = ca.certPEM
+	if err := testTLSConnect(t, cfg); err != nil {
+		t.Fatalf("expected the CA given as PEM to be trusted, got %s", err)
+	}
+
+	cfg.CACertPEM = ""
+	cfg.CACertFile = filepath.Join(t.TempDir(), "ca.pem")
+	if err := ioutil.WriteFile(cfg.CACertFile, []byte(ca.certPEM), 0600); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	if err := testTLSConnect(t, cfg); err != nil {
+		t.Fatalf("expected the CA given as a file to be trusted, got %s", err)
+	}
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	ca := newTestCert(t, "test ca", nil, 0)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	s := newTLSTestServer(t, ca, func(c *tls.Config) {
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+		c.ClientCAs = pool
+	})
+	client := newTestCert(t, "terraform", ca, x509.ExtKeyUsageClientAuth)
+
+	cfg := s.Cfg()
+	cfg.TLSVerify = true
+	cfg.CACertPEM = ca.certPEM
+	if err := testTLSConnect(t, cfg); err == nil {
+		t.Fatalf("expected the server to refuse a client without a certificate")
+	}
+
+	cfg.ClientCert = client.certPEM
+	cfg.ClientKey = client.keyPEM
+	if err := testTLSConnect(t, cfg); err != nil {
+		t.Fatalf("expected the client certificate given as PEM to be accepted, got %s", err)
+	}
+
+	dir := t.TempDir()
+	cfg.ClientCert = filepath.Join(dir, "client.pem")
+	cfg.ClientKey = filepath.Join(dir, "client.key")
+	if err := ioutil.WriteFile(cfg.ClientCert, []byte(client.certPEM), 0600); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	if err := ioutil.WriteFile(cfg.ClientKey, []byte(client.keyPEM), 0600); err != nil {
+		t.Fatalf("WriteFile: %s", err)
+	}
+	if err := testTLSConnect(t, cfg); err != nil {
+		t.Fatalf("expected the client certificate given as files to be accepted, got %s", err)
+	}
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	ca := newTestCert(t, "test ca", nil, 0)
+	s := newTLSTestServer(t, ca, func(c *tls.Config) {
+		c.MaxVersion = tls.VersionTLS12
+	})
+
+	cfg := s.Cfg()
+	cfg.MinTLSVersion = "1.2"
+	if err := testTLSConnect(t, cfg); err != nil {
+		t.Fatalf("expected TLS 1.2 to be accepted, got %s", err)
+	}
+
+	cfg.MinTLSVersion = "1.3"
+	if err := testTLSConnect(t, cfg); err == nil {
+		t.Fatalf("expected a server limited to TLS 1.2 to be rejected")
+	}
+}
+
+func TestTLSInvalidSettings(t *testing.T) {
+	ca := newTestCert(t, "test ca", nil, 0)
+	client := newTestCert(t, "terraform", ca, x509.ExtKeyUsageClientAuth)
+	tests := map[string]func(*infoblox.Cfg){
+		"unknown version":  func(c *infoblox.Cfg) { c.MinTLSVersion = "1.4" },
+		"missing CA file":  func(c *infoblox.Cfg) { c.CACertFile = filepath.Join(t.TempDir(), "missing.pem") },
+		"CA without certs": func(c *infoblox.Cfg) { c.CACertPEM = "not a certificate" },
+		"cert without key": func(c *infoblox.Cfg) { c.ClientCert = client.certPEM },
+		"mismatched key": func(c *infoblox.Cfg) {
+			c.ClientCert = client.certPEM
+			c.ClientKey = ca.keyPEM
+		},
+	}
+	for name, set := range tests {
+		cfg := &infoblox.Cfg{Host: "infoblox.example.com", WAPIVersion: "2.10.1", Username: "admin", Password: "infoblox"}
+		set(cfg)
+		if _, err := infoblox.ClientInit(cfg); err == nil {
+			t.Errorf("%s: expected ClientInit to fail", name)
+		}
+	}
+}
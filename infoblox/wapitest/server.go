@@ -0,0 +1,1081 @@
+// Package wapitest provides an in-memory stand-in for the infoblox WAPI so the
+// infoblox client and the terraform resources built on it can be tested offline
+package wapitest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+// Default credentials and version accepted by a new Server
+const (
+	Username    = "admin"
+	Password    = "infoblox"
+	WAPIVersion = "2.10.1"
+	// DefaultMaxResults is the most objects a search returns without paging, as on a grid
+	DefaultMaxResults = 1000
+)
+
+// objectType describes how the fake stores one WAPI object type
+type objectType struct {
+	// required fields that must be supplied on create
+	required []string
+	// identity fields that must be unique within a view, infoblox rejects duplicates as a conflict
+	identity []string
+	// default return fields when _return_fields isn't given
+	defaults []string
+	// unknown fields other record types have but this one doesn't, writing them is rejected
+	unknown []string
+	// derive fills in fields infoblox computes from the ones written, given the object and the fields changed
+	derive func(fields map[string]interface{}, changed map[string]interface{}) *wapiError
+}
+
+// checkFields rejects writes of fields the type doesn't have, as infoblox does
+func (ot objectType) checkFields(fields map[string]interface{}) *wapiError {
+	for _, f := range ot.unknown {
+		if _, ok := fields[f]; ok {
+			return badRequest("Unknown argument/field: '" + f + "'")
+		}
+	}
+	return nil
+}
+
+var objectTypes = map[string]objectType{
+	"record:a": {
+		required: []string{"name", "ipv4addr"},
+		identity: []string{"name", "ipv4addr"},
+		defaults: []string{"ipv4addr", "name", "view"},
+	},
+	"record:cname": {
+		required: []string{"name", "canonical"},
+		identity: []string{"name"},
+		defaults: []string{"canonical", "name", "view"},
+	},
+	"record:txt": {
+		required: []string{"name", "text"},
+		identity: []string{"name", "text"},
+		defaults: []string{"name", "text", "view"},
+	},
+	"record:aaaa": {
+		required: []string{"name", "ipv6addr"},
+		identity: []string{"name", "ipv6addr"},
+		defaults: []string{"ipv6addr", "name", "view"},
+	},
+	"record:ptr": {
+		required: []string{"name", "ptrdname"},
+		identity: []string{"name", "ptrdname"},
+		defaults: []string{"ptrdname", "view"},
+		derive:   derivePtrName,
+	},
+	"record:mx": {
+		required: []string{"name", "mail_exchanger", "preference"},
+		identity: []string{"name", "mail_exchanger", "preference"},
+		defaults: []string{"mail_exchanger", "name", "preference", "view"},
+	},
+	"record:srv": {
+		required: []string{"name", "priority", "weight", "port", "target"},
+		identity: []string{"name", "priority", "weight", "port", "target"},
+		defaults: []string{"name", "port", "priority", "target", "view", "weight"},
+	},
+	"record:host": {
+		required: []string{"name"},
+		identity: []string{"name"},
+		defaults: []string{"ipv4addrs", "ipv6addrs", "name", "view"},
+		unknown:  []string{"creator"},
+		derive:   deriveHostAddresses,
+	},
+	"extensibleattributedef": {
+		required: []string{"name", "type"},
+		identity: []string{"name"},
+		defaults: []string{"name", "type"},
+	},
+}
+
+// recordDefaults are the values infoblox gives fields every record has when a create leaves them out
+var recordDefaults = map[string]interface{}{
+	"view":    "default",
+	"disable": false,
+	"use_ttl": false,
+	"creator": "STATIC",
+}
+
+// Server is a TLS httptest server answering a subset of WAPI requests from memory
+type Server struct {
+	*httptest.Server
+
+	Username    string
+	Password    string
+	WAPIVersion string
+	// Latency delays every response, set it before sending requests
+	Latency time.Duration
+	// MaxResults is the most objects a search without paging or _max_results can return before
+	// failing, set it before sending requests
+	MaxResults int
+
+	mu          sync.Mutex
+	nextID      int
+	objects     map[string]*object
+	faults      []fault
+	requests    int
+	inFlight    int
+	maxInFlight int
+	sessions    map[string]bool
+	logins      int
+	batches     int
+	nextPage    int
+	pages       map[string]pagedSearch
+}
+
+// pagedSearch holds the results of a paged search still to be fetched
+type pagedSearch struct {
+	results []map[string]interface{}
+	size    int
+}
+
+// fault is a failure injected in place of handling a request, a zero status drops the connection
+type fault struct {
+	// method limits the fault to requests of one HTTP method, any request when empty
+	method     string
+	status     int
+	retryAfter string
+}
+
+// object is a stored WAPI object, the id is fixed at creation while the _ref follows name and view
+type object struct {
+	objType string
+	id      string
+	fields  map[string]interface{}
+}
+
+func (o *object) ref() string {
+	if !strings.HasPrefix(o.objType, "record:") {
+		// objects outside of DNS views have no view in their _ref
+		return fmt.Sprintf("%s/%s:%v", o.objType, o.id, o.fields["name"])
+	}
+	return fmt.Sprintf("%s/%s:%v/%v", o.objType, o.id, o.fields["name"], o.fields["view"])
+}
+
+// NewServer starts a fake WAPI accepting the default credentials, callers must Close it
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.StartTLS()
+	return s
+}
+
+// NewUnstartedServer returns a fake WAPI that isn't listening yet, so its TLS settings
+// can be changed before calling StartTLS
+func NewUnstartedServer() *Server {
+	s := &Server{
+		Username:    Username,
+		Password:    Password,
+		WAPIVersion: WAPIVersion,
+		MaxResults:  DefaultMaxResults,
+		objects:     map[string]*object{},
+		sessions:    map[string]bool{},
+		pages:       map[string]pagedSearch{},
+	}
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Host returns the host:port the server listens on, as used in infoblox.Cfg
+func (s *Server) Host() string {
+	return strings.TrimPrefix(s.URL, "https://")
+}
+
+// Cfg returns a client config pointing at the server
+func (s *Server) Cfg() *infoblox.Cfg {
+	return &infoblox.Cfg{
+		Host:        s.Host(),
+		WAPIVersion: s.WAPIVersion,
+		Username:    s.Username,
+		Password:    s.Password,
+		TLSVerify:   false,
+		Timeout:     10,
+	}
+}
+
+// Create stores an object directly, bypassing the API, as if someone had created it by hand.
+// It returns the object's _ref.
+func (s *Server) Create(objType string, fields map[string]interface{}) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ref, werr := s.create(objType, fields)
+	if werr != nil {
+		return "", werr
+	}
+	return ref, nil
+}
+
+// Update changes fields of a stored object directly, as if someone had edited it by hand.
+// It returns the object's new _ref.
+func (s *Server) Update(ref string, fields map[string]interface{}) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	newRef, werr := s.update(ref, fields)
+	if werr != nil {
+		return "", werr
+	}
+	return newRef, nil
+}
+
+// Delete removes a stored object directly, as if someone had deleted it by hand
+func (s *Server) Delete(ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.objects[ref]; !ok {
+		return notFound(ref)
+	}
+	delete(s.objects, ref)
+	return nil
+}
+
+// Fail makes the next count requests fail with status before they reach the stored objects.
+// retryAfter, when not empty, is sent as the Retry-After header.
+func (s *Server) Fail(count int, status int, retryAfter string) {
+	s.FailMethod("", count, status, retryAfter)
+}
+
+// FailMethod is Fail for requests of one HTTP method only, other requests are served as usual
+func (s *Server) FailMethod(method string, count int, status int, retryAfter string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < count; i++ {
+		s.faults = append(s.faults, fault{method: method, status: status, retryAfter: retryAfter})
+	}
+}
+
+// Drop makes the next count requests close the connection without sending a response
+func (s *Server) Drop(count int) {
+	s.Fail(count, 0, "")
+}
+
+// Requests returns how many requests the server has received, including failed ones
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Logins returns how many requests authenticated with basic auth rather than a session cookie
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// ExpireSessions invalidates every ibapauth session cookie issued so far, as infoblox does on timeout
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = map[string]bool{}
+}
+
+// Batches returns how many multi-object requests the server has applied
+func (s *Server) Batches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+// MaxInFlight returns the most requests the server has handled at the same time
+func (s *Server) MaxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.maxInFlight
+}
+
+// Get returns a copy of every field of a stored object including _ref, or nil if it doesn't exist
+func (s *Server) Get(ref string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[ref]
+	if !ok {
+		return nil
+	}
+	return obj.all()
+}
+
+// Objects returns copies of every stored object of a type ordered by _ref
+func (s *Server) Objects(objType string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var objs []map[string]interface{}
+	for _, ref := range s.sortedRefs() {
+		if obj := s.objects[ref]; obj.objType == objType {
+			objs = append(objs, obj.all())
+		}
+	}
+	return objs
+}
+
+// wapiError is the JSON error body infoblox returns
+type wapiError struct {
+	status int
+	Err    string `json:"Error"`
+	Code   string `json:"code"`
+	Text   string `json:"text"`
+}
+
+func (e *wapiError) Error() string {
+	return e.Text
+}
+
+func notFound(ref string) *wapiError {
+	return &wapiError{
+		status: http.StatusNotFound,
+		Err:    "AdmConDataNotFoundError: Reference " + ref + " not found",
+		Code:   "Client.Ibap.Data.NotFound",
+		Text:   "Reference " + ref + " not found",
+	}
+}
+
+func badRequest(text string) *wapiError {
+	return &wapiError{
+		status: http.StatusBadRequest,
+		Err:    "AdmConProtoError: " + text,
+		Code:   "Client.Ibap.Proto",
+		Text:   text,
+	}
+}
+
+func conflict(text string) *wapiError {
+	return &wapiError{
+		status: http.StatusBadRequest,
+		Err:    "AdmConDataError: None (IBDataConflictError: IB.Data.Conflict:" + text + ")",
+		Code:   "Client.Ibap.Data.Conflict",
+		Text:   text,
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	defer s.track()()
+	time.Sleep(s.Latency)
+
+	if f, ok := s.nextFault(r.Method); ok {
+		if f.status == 0 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		http.Error(w, http.StatusText(f.status), f.status)
+		return
+	}
+
+	if !s.authenticate(w, r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="InfoBlox ONE Platform"`)
+		http.Error(w, "Authorization Required", http.StatusUnauthorized)
+		return
+	}
+
+	prefix := "/wapi/v" + s.WAPIVersion + "/"
+	if !strings.HasPrefix(r.URL.Path+"/", prefix) {
+		writeError(w, badRequest("Unknown WAPI version in "+r.URL.Path))
+		return
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, strings.TrimSuffix(prefix, "/")), "/")
+
+	if path == "request" && r.Method == http.MethodPost {
+		var ops []batchOp
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil || json.Unmarshal(raw, &ops) != nil {
+			writeError(w, badRequest("Invalid JSON body"))
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		results, werr := s.batch(ops)
+		if werr != nil {
+			writeError(w, werr)
+			return
+		}
+		writeJSON(w, http.StatusOK, results)
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		raw, err := ioutil.ReadAll(r.Body)
+		if err != nil || json.Unmarshal(raw, &body) != nil {
+			writeError(w, badRequest("Invalid JSON body"))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objType, isRef := path, false
+	if i := strings.Index(path, "/"); i != -1 {
+		objType, isRef = path[:i], true
+	}
+	if path == "" {
+		writeError(w, badRequest("No object type given"))
+		return
+	}
+	if _, ok := objectTypes[objType]; !ok {
+		writeError(w, badRequest("Unknown object type ("+objType+")"))
+		return
+	}
+
+	var status int
+	var result interface{}
+	var werr *wapiError
+	switch {
+	case r.Method == http.MethodGet && isRef:
+		status = http.StatusOK
+		result, werr = s.read(path, r)
+	case r.Method == http.MethodGet:
+		status = http.StatusOK
+		result, werr = s.search(objType, r)
+	case r.Method == http.MethodPost && !isRef:
+		status = http.StatusCreated
+		result, werr = s.create(objType, body)
+	case r.Method == http.MethodPut && isRef:
+		status = http.StatusOK
+		result, werr = s.update(path, body)
+	case r.Method == http.MethodDelete && isRef:
+		status = http.StatusOK
+		result, werr = s.remove(path)
+	default:
+		werr = badRequest(r.Method + " not supported on " + path)
+	}
+	if werr != nil {
+		writeError(w, werr)
+		return
+	}
+	writeJSON(w, status, result)
+}
+
+// track records a request as in flight until the returned func is called
+func (s *Server) track() func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.inFlight--
+	}
+}
+
+// authenticate accepts a live ibapauth session cookie or valid basic auth credentials,
+// issuing a new session cookie for the latter
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, err := r.Cookie("ibapauth"); err == nil && s.sessions[c.Value] {
+		return true
+	}
+	user, pass, ok := r.BasicAuth()
+	if !ok || user != s.Username || pass != s.Password {
+		return false
+	}
+	s.logins++
+	token := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("session-%d", s.logins)))
+	s.sessions[token] = true
+	http.SetCookie(w, &http.Cookie{Name: "ibapauth", Value: token, Path: "/", Secure: true, HttpOnly: true})
+	return true
+}
+
+// nextFault counts the request and returns the failure to inject for it, if any
+func (s *Server) nextFault(method string) (fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	for n, f := range s.faults {
+		if f.method == "" || f.method == method {
+			s.faults = append(s.faults[:n], s.faults[n+1:]...)
+			return f, true
+		}
+	}
+	return fault{}, false
+}
+
+func (s *Server) read(ref string, r *http.Request) (interface{}, *wapiError) {
+	obj, ok := s.objects[ref]
+	if !ok {
+		return nil, notFound(ref)
+	}
+	return obj.project(r), nil
+}
+
+// search finds the objects matching the query, applying the _max_results, _paging and _page_id controls
+func (s *Server) search(objType string, r *http.Request) (interface{}, *wapiError) {
+	q := r.URL.Query()
+	if id := q.Get("_page_id"); id != "" {
+		p, ok := s.pages[id]
+		if !ok {
+			return nil, badRequest("Page id " + id + " is not valid")
+		}
+		delete(s.pages, id)
+		return s.page(p.results, p.size), nil
+	}
+
+	maxResults := s.MaxResults
+	if v := q.Get("_max_results"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n == 0 {
+			return nil, badRequest("Invalid value for _max_results: " + v)
+		}
+		maxResults = n
+	}
+	results, werr := s.find(objType, r)
+	if werr != nil {
+		return nil, werr
+	}
+
+	if q.Get("_paging") == "1" {
+		if q.Get("_return_as_object") != "1" {
+			return nil, badRequest("_return_as_object must be set when paging")
+		}
+		if maxResults < 0 {
+			maxResults = -maxResults
+		}
+		return s.page(results, maxResults), nil
+	}
+	if maxResults < 0 {
+		// a negative limit truncates rather than fails
+		if len(results) > -maxResults {
+			results = results[:-maxResults]
+		}
+	} else if len(results) > maxResults {
+		return nil, badRequest(fmt.Sprintf("Result set too large (> %d)", maxResults))
+	}
+	if q.Get("_return_as_object") == "1" {
+		return map[string]interface{}{"result": results}, nil
+	}
+	return results, nil
+}
+
+// page returns the first size results, keeping the rest for a later _page_id request
+func (s *Server) page(results []map[string]interface{}, size int) map[string]interface{} {
+	if len(results) <= size {
+		return map[string]interface{}{"result": results}
+	}
+	s.nextPage++
+	id := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("page-%d", s.nextPage)))
+	s.pages[id] = pagedSearch{results: results[size:], size: size}
+	return map[string]interface{}{"result": results[:size], "next_page_id": id}
+}
+
+// find returns the projection of every object of a type matching the query, ordered by _ref
+func (s *Server) find(objType string, r *http.Request) ([]map[string]interface{}, *wapiError) {
+	results := []map[string]interface{}{}
+	for _, ref := range s.sortedRefs() {
+		obj := s.objects[ref]
+		if obj.objType != objType {
+			continue
+		}
+		match, werr := obj.matches(r)
+		if werr != nil {
+			return nil, werr
+		}
+		if match {
+			results = append(results, obj.project(r))
+		}
+	}
+	return results, nil
+}
+
+func (s *Server) create(objType string, fields map[string]interface{}) (string, *wapiError) {
+	ot, ok := objectTypes[objType]
+	if !ok {
+		return "", badRequest("Unknown object type (" + objType + ")")
+	}
+	if werr := ot.checkFields(fields); werr != nil {
+		return "", werr
+	}
+	obj := &object{objType: objType, fields: copyObject(fields)}
+	for k, v := range obj.fields {
+		obj.fields[k] = canonical(k, v)
+	}
+	for k, v := range recordDefaults {
+		if _, ok := obj.fields[k]; !ok && strings.HasPrefix(objType, "record:") {
+			obj.fields[k] = v
+		}
+	}
+	if eas, ok := obj.fields["extattrs"].(map[string]interface{}); ok {
+		if werr := s.checkExtAttrs(eas); werr != nil {
+			return "", werr
+		}
+	}
+	for _, f := range ot.unknown {
+		delete(obj.fields, f)
+	}
+	if ot.derive != nil {
+		if werr := ot.derive(obj.fields, fields); werr != nil {
+			return "", werr
+		}
+	}
+	for _, f := range ot.required {
+		if _, ok := obj.fields[f]; !ok {
+			return "", badRequest("field for create missing: " + f)
+		}
+	}
+	if s.findDuplicate(obj, "") != "" {
+		return "", conflict(fmt.Sprintf("The record '%v' already exists.", obj.fields["name"]))
+	}
+
+	s.nextID++
+	obj.id = base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("dns.bind_%s$.%d", strings.TrimPrefix(objType, "record:"), s.nextID)))
+	ref := obj.ref()
+	s.objects[ref] = obj
+	return ref, nil
+}
+
+func (s *Server) update(ref string, fields map[string]interface{}) (string, *wapiError) {
+	obj, ok := s.objects[ref]
+	if !ok {
+		return "", notFound(ref)
+	}
+	if werr := objectTypes[obj.objType].checkFields(fields); werr != nil {
+		return "", werr
+	}
+	updated := &object{objType: obj.objType, id: obj.id, fields: copyObject(obj.fields)}
+	for k, v := range fields {
+		switch {
+		case k == "view" && v != obj.fields["view"]:
+			return "", badRequest("Field is not writable: view")
+		case k == "extattrs+" || k == "extattrs-":
+			changes, ok := v.(map[string]interface{})
+			if !ok {
+				return "", badRequest("Invalid value for " + k)
+			}
+			if k == "extattrs+" {
+				if werr := s.checkExtAttrs(changes); werr != nil {
+					return "", werr
+				}
+			}
+			// copy so the stored object is never changed in place
+			eas, _ := updated.fields["extattrs"].(map[string]interface{})
+			eas = copyObject(eas)
+			for name, ea := range changes {
+				if k == "extattrs+" {
+					eas[name] = ea
+				} else {
+					delete(eas, name)
+				}
+			}
+			updated.fields["extattrs"] = eas
+		default:
+			updated.fields[k] = canonical(k, v)
+		}
+	}
+	if derive := objectTypes[obj.objType].derive; derive != nil {
+		if werr := derive(updated.fields, fields); werr != nil {
+			return "", werr
+		}
+	}
+	if s.findDuplicate(updated, ref) != "" {
+		return "", conflict(fmt.Sprintf("The record '%v' already exists.", updated.fields["name"]))
+	}
+	delete(s.objects, ref)
+	newRef := updated.ref()
+	s.objects[newRef] = updated
+	return newRef, nil
+}
+
+func (s *Server) remove(ref string) (string, *wapiError) {
+	if _, ok := s.objects[ref]; !ok {
+		return "", notFound(ref)
+	}
+	delete(s.objects, ref)
+	return ref, nil
+}
+
+// batchOp is one operation of a multi-object request
+type batchOp struct {
+	Method string                 `json:"method"`
+	Object string                 `json:"object"`
+	Data   map[string]interface{} `json:"data"`
+}
+
+// batch applies ops as one transaction, rolling every op back if any fails as infoblox does
+func (s *Server) batch(ops []batchOp) ([]interface{}, *wapiError) {
+	saved, savedID := make(map[string]*object, len(s.objects)), s.nextID
+	for ref, obj := range s.objects {
+		saved[ref] = obj
+	}
+
+	results := make([]interface{}, 0, len(ops))
+	for i, op := range ops {
+		var result string
+		var werr *wapiError
+		isRef := strings.Contains(op.Object, "/")
+		switch {
+		case op.Method == http.MethodPost && !isRef:
+			result, werr = s.create(op.Object, op.Data)
+		case op.Method == http.MethodPut && isRef:
+			result, werr = s.update(op.Object, op.Data)
+		case op.Method == http.MethodDelete && isRef:
+			result, werr = s.remove(op.Object)
+		default:
+			werr = badRequest(op.Method + " not supported on " + op.Object)
+		}
+		if werr != nil {
+			s.objects, s.nextID = saved, savedID
+			werr.Text = fmt.Sprintf("%s (in request %d)", werr.Text, i)
+			return nil, werr
+		}
+		results = append(results, result)
+	}
+	s.batches++
+	return results, nil
+}
+
+// findDuplicate returns the ref of another object with the same identity in the same view
+// checkExtAttrs rejects extensible attribute values that don't suit the type of their definition, as
+// infoblox does. Attributes without a definition are accepted so tests needn't define every one.
+func (s *Server) checkExtAttrs(eas map[string]interface{}) *wapiError {
+	for name, ea := range eas {
+		def := s.extAttrDef(name)
+		if def == nil {
+			continue
+		}
+		attr, _ := ea.(map[string]interface{})
+		values, isList := attr["value"].([]interface{})
+		if !isList {
+			values = []interface{}{attr["value"]}
+		} else if !strings.Contains(fmt.Sprint(def["flags"]), "M") {
+			return badRequest("Extensible attribute '" + name + "' does not allow multiple values")
+		}
+		for _, v := range values {
+			var ok bool
+			switch v.(type) {
+			case float64, int, int64:
+				ok = def["type"] == infoblox.ExtAttrTypeInteger
+			case string:
+				ok = def["type"] != infoblox.ExtAttrTypeInteger
+			}
+			if !ok {
+				return badRequest(fmt.Sprintf("Invalid value %#v for extensible attribute '%s' of type %v", v, name, def["type"]))
+			}
+		}
+	}
+	return nil
+}
+
+// extAttrDef returns the fields of the definition of an extensible attribute, nil if there isn't one
+func (s *Server) extAttrDef(name string) map[string]interface{} {
+	for _, obj := range s.objects {
+		if obj.objType == "extensibleattributedef" && obj.fields["name"] == name {
+			return obj.fields
+		}
+	}
+	return nil
+}
+
+func (s *Server) findDuplicate(obj *object, self string) string {
+	ot := objectTypes[obj.objType]
+	for ref, other := range s.objects {
+		if ref == self || other.objType != obj.objType || other.fields["view"] != obj.fields["view"] {
+			continue
+		}
+		same := true
+		for _, f := range ot.identity {
+			if fmt.Sprint(other.fields[f]) != fmt.Sprint(obj.fields[f]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return ref
+		}
+	}
+	return ""
+}
+
+func (s *Server) sortedRefs() []string {
+	refs := make([]string, 0, len(s.objects))
+	for ref := range s.objects {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// matches reports whether the object satisfies every search argument in the query string.
+// WAPI modifiers go between the field and =, such as name~=, and *name searches an extensible attribute.
+func (o *object) matches(r *http.Request) (bool, *wapiError) {
+	for key, values := range r.URL.Query() {
+		if strings.HasPrefix(key, "_") {
+			continue
+		}
+		field := strings.TrimRight(key, "!:~<>")
+		mods := key[len(field):]
+		v, ok := o.value(field)
+		for _, want := range values {
+			if mods == "" {
+				want = fmt.Sprint(canonical(field, want))
+			}
+			match, werr := compare(v, ok, want, mods)
+			if werr != nil || !match {
+				return false, werr
+			}
+		}
+	}
+	return true, nil
+}
+
+// value returns a field as searched. *name reads an extensible attribute, and the zone of
+// records stored without one is taken to be the name less its first label.
+func (o *object) value(field string) (interface{}, bool) {
+	if strings.HasPrefix(field, "*") {
+		ea, _ := o.fields["extattrs"].(map[string]interface{})
+		attr, ok := ea[field[1:]].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok := attr["value"]
+		return v, ok
+	}
+	if _, ok := o.fields["zone"]; field == "zone" && !ok {
+		name := fmt.Sprint(o.fields["name"])
+		i := strings.Index(name, ".")
+		if i == -1 {
+			return nil, false
+		}
+		return name[i+1:], true
+	}
+	v, ok := o.fields[field]
+	return v, ok
+}
+
+// compare applies a search argument with its modifiers to a field value, a list matches if any item does
+func compare(v interface{}, ok bool, want string, mods string) (bool, *wapiError) {
+	negate := strings.Contains(mods, "!")
+	if !ok {
+		return negate, nil
+	}
+	items, isList := v.([]interface{})
+	if !isList {
+		items = []interface{}{v}
+	}
+
+	var matcher func(string) bool
+	switch {
+	case strings.Contains(mods, "~"):
+		pattern := want
+		if strings.Contains(mods, ":") {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, badRequest("Invalid regular expression " + want)
+		}
+		matcher = re.MatchString
+	case strings.Contains(mods, "<"):
+		matcher = func(got string) bool { return compareOrdered(got, want) <= 0 }
+	case strings.Contains(mods, ">"):
+		matcher = func(got string) bool { return compareOrdered(got, want) >= 0 }
+	case strings.Contains(mods, ":"):
+		matcher = func(got string) bool { return strings.EqualFold(got, want) }
+	default:
+		matcher = func(got string) bool { return got == want }
+	}
+
+	for _, item := range items {
+		if matcher(fmt.Sprint(item)) {
+			return !negate, nil
+		}
+	}
+	return negate, nil
+}
+
+// compareOrdered compares numerically when both values are numbers, such as a ttl, and as strings otherwise
+func compareOrdered(a string, b string) int {
+	x, errX := strconv.ParseFloat(a, 64)
+	y, errY := strconv.ParseFloat(b, 64)
+	if errX != nil || errY != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// project returns the fields requested by _return_fields or _return_fields+, plus _ref
+func (o *object) project(r *http.Request) map[string]interface{} {
+	fields := objectTypes[o.objType].defaults
+	q := r.URL.Query()
+	if rf, ok := q["_return_fields"]; ok {
+		fields = splitFields(rf)
+	} else if rf, ok := q["_return_fields+"]; ok {
+		fields = append(append([]string{}, fields...), splitFields(rf)...)
+	}
+
+	out := map[string]interface{}{"_ref": o.ref()}
+	for _, f := range fields {
+		if v, ok := o.fields[f]; ok {
+			out[f] = v
+		}
+	}
+	return out
+}
+
+// all returns a copy of every field plus _ref
+func (o *object) all() map[string]interface{} {
+	out := copyObject(o.fields)
+	out["_ref"] = o.ref()
+	return out
+}
+
+func splitFields(values []string) []string {
+	var fields []string
+	for _, v := range values {
+		for _, f := range strings.Split(v, ",") {
+			if f != "" {
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
+// derivePtrName sets the name of a PTR record written by address to the reverse name, as infoblox
+// does. An address no longer matching a name written on its own is dropped.
+func derivePtrName(fields map[string]interface{}, changed map[string]interface{}) *wapiError {
+	_, v4 := changed["ipv4addr"]
+	_, v6 := changed["ipv6addr"]
+	if v4 && v6 {
+		return badRequest("Only one of ipv4addr and ipv6addr can be set")
+	}
+	for _, f := range []string{"ipv4addr", "ipv6addr"} {
+		if _, ok := changed[f]; !ok {
+			continue
+		}
+		name, err := infoblox.ReverseName(fmt.Sprint(fields[f]))
+		if err != nil {
+			return badRequest(err.Error())
+		}
+		fields["name"] = name
+	}
+	for _, f := range []string{"ipv4addr", "ipv6addr"} {
+		addr, ok := fields[f].(string)
+		if !ok {
+			continue
+		}
+		if name, _ := infoblox.ReverseName(addr); name != fields["name"] {
+			delete(fields, f)
+		}
+	}
+	return nil
+}
+
+// deriveHostAddresses gives every address of a host record its own _ref and the host name, as
+// infoblox does, and checks DHCP is only configured for addresses with a client identifier
+func deriveHostAddresses(fields map[string]interface{}, changed map[string]interface{}) *wapiError {
+	if _, ok := fields["configure_for_dns"]; !ok {
+		fields["configure_for_dns"] = true
+	}
+	count := 0
+	for _, f := range []struct{ list, addr, client string }{
+		{"ipv4addrs", "ipv4addr", "mac"},
+		{"ipv6addrs", "ipv6addr", "duid"},
+	} {
+		v, ok := fields[f.list]
+		if !ok {
+			continue
+		}
+		list, ok := v.([]interface{})
+		if !ok {
+			return badRequest("Invalid value for " + f.list)
+		}
+		addrs := make([]interface{}, len(list))
+		for i, item := range list {
+			in, ok := item.(map[string]interface{})
+			if !ok {
+				return badRequest("Invalid value for " + f.list)
+			}
+			// copy so the stored object is never changed in place
+			addr := make(map[string]interface{}, len(in)+3)
+			for k, v := range in {
+				addr[k] = canonical(k, v)
+			}
+			ip, ok := addr[f.addr].(string)
+			if !ok || net.ParseIP(ip) == nil {
+				return badRequest(fmt.Sprintf("Invalid %s in %s: %v", f.addr, f.list, addr[f.addr]))
+			}
+			if _, ok := addr["configure_for_dhcp"]; !ok {
+				addr["configure_for_dhcp"] = false
+			}
+			if addr["configure_for_dhcp"] == true && addr[f.client] == nil {
+				return badRequest(fmt.Sprintf("%s is required to configure %s for DHCP", f.client, ip))
+			}
+			addr["host"] = fields["name"]
+			addr["_ref"] = fmt.Sprintf("record:host_%s/%s:%s/%v", f.addr,
+				base64.RawURLEncoding.EncodeToString([]byte(ip)), ip, fields["view"])
+			addrs[i] = addr
+		}
+		fields[f.list] = addrs
+		count += len(addrs)
+	}
+	if count == 0 {
+		return badRequest("A host record needs at least one address")
+	}
+	return nil
+}
+
+// canonical returns a field value in the form infoblox stores it, which compresses IPv6 addresses
+// so 2001:0db8:0:0::1 is kept and searched for as 2001:db8::1, and writes MAC addresses in lower case
+func canonical(field string, v interface{}) interface{} {
+	text, ok := v.(string)
+	if !ok {
+		return v
+	}
+	switch field {
+	case "ipv6addr":
+		if ip := net.ParseIP(text); ip != nil {
+			return ip.String()
+		}
+	case "mac":
+		if mac, err := net.ParseMAC(text); err == nil {
+			return mac.String()
+		}
+	}
+	return v
+}
+
+func copyObject(obj map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		c[k] = v
+	}
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, e *wapiError) {
+	writeJSON(w, e.status, e)
+}
@@ -1,15 +1,12 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform/plugin"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/hiscox/terraform-provider-infoblox/resources"
 )
 
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() terraform.ResourceProvider {
-			return resources.Provider()
-		},
+		ProviderFunc: resources.Provider,
 	})
 }
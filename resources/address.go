@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// canonicalIP returns an address in the form infoblox stores it, compressing IPv6 addresses
+// so 2001:0db8:0:0::1 becomes 2001:db8::1. Values that aren't addresses are returned unchanged.
+func canonicalIP(addr string) string {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return addr
+	}
+	return ip.String()
+}
+
+// canonicalMAC returns a MAC address in the lower case, colon separated form infoblox stores.
+// Values that aren't MAC addresses are returned unchanged.
+func canonicalMAC(addr string) string {
+	mac, err := net.ParseMAC(addr)
+	if err != nil {
+		return addr
+	}
+	return mac.String()
+}
+
+// suppressEquivalentIP hides the difference between two ways of writing the same address,
+// infoblox reports IPv6 addresses compressed whatever form they were given in
+func suppressEquivalentIP(k, old, new string, d *schema.ResourceData) bool {
+	return canonicalIP(old) == canonicalIP(new)
+}
+
+// validateIPv6 accepts IPv6 addresses only, the SDK IsIPv6Address also passes IPv4 addresses
+func validateIPv6(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if ip := net.ParseIP(v); ip == nil || ip.To4() != nil {
+		return nil, []error{fmt.Errorf("expected %s to contain a valid IPv6 address, got: %s", k, v)}
+	}
+	return nil, nil
+}
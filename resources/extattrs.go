@@ -0,0 +1,187 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+// extAttrsSchema holds the extensible attributes of a record
+func extAttrsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "Extensible attributes of the record, converted to the type of their definition in infoblox. Give the values of multi-value attributes as a JSON list such as jsonencode([\"a\", \"b\"])",
+	}
+}
+
+// extAttrDefs returns the definitions of the named extensible attributes, fetched from infoblox the
+// first time they are needed and again when an attribute defined since is asked for
+func (meta *providerMeta) extAttrDefs(ctx context.Context, names []string) (map[string]infoblox.ExtAttrDef, error) {
+	meta.mu.Lock()
+	defer meta.mu.Unlock()
+	for _, name := range names {
+		if _, ok := meta.defs[name]; !ok {
+			defs, err := meta.client.IbExtAttrDefs(ctx)
+			if err != nil {
+				return nil, err
+			}
+			meta.defs = defs
+			break
+		}
+	}
+	return meta.defs, nil
+}
+
+// expandExtAttrs converts the extattrs attribute to the form infoblox expects, each value taking
+// the type of the attribute's definition
+func expandExtAttrs(ctx context.Context, m interface{}, v map[string]interface{}) (map[string]infoblox.ExtAttr, error) {
+	eas := make(map[string]infoblox.ExtAttr, len(v))
+	if len(v) == 0 {
+		return eas, nil
+	}
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	defs, err := m.(*providerMeta).extAttrDefs(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range v {
+		def, ok := defs[name]
+		if !ok {
+			// infoblox rejects attributes without a definition with its own error
+			eas[name] = infoblox.ExtAttr{Value: value.(string)}
+			continue
+		}
+		ea, err := extAttrValue(def, value.(string))
+		if err != nil {
+			return nil, err
+		}
+		eas[name] = infoblox.ExtAttr{Value: ea}
+	}
+	return eas, nil
+}
+
+// extAttrValue converts a value to the type of the attribute's definition. Attributes allowing
+// multiple values take a JSON list, a value that isn't one is a single value.
+func extAttrValue(def infoblox.ExtAttrDef, s string) (interface{}, error) {
+	list, ok := extAttrList(s)
+	if !def.Multiple() || !ok {
+		return extAttrSingleValue(def, s)
+	}
+	values := make([]interface{}, len(list))
+	for i, item := range list {
+		v, err := extAttrSingleValue(def, extAttrText(item))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func extAttrSingleValue(def infoblox.ExtAttrDef, s string) (interface{}, error) {
+	if def.Type != infoblox.ExtAttrTypeInteger {
+		return s, nil
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Extensible attribute %s is an INTEGER in infoblox but %q isn't a whole number", def.Name, s)
+	}
+	return n, nil
+}
+
+// extAttrList decodes a JSON list of values
+func extAttrList(s string) ([]interface{}, bool) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var list []interface{}
+	if dec.Decode(&list) != nil || dec.Decode(new(interface{})) != io.EOF {
+		return nil, false
+	}
+	return list, true
+}
+
+// extAttrText returns an item of a JSON list as text
+func extAttrText(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// flattenExtAttrs converts the extensible attributes read from infoblox to the extattrs attribute.
+// Attributes the provider ignores are left out unless terraform already manages them, and a managed
+// value written differently from how infoblox returns it is kept as written.
+func flattenExtAttrs(eas map[string]infoblox.ExtAttr, ignored map[string]bool, managed map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(eas))
+	for name, ea := range eas {
+		local, ok := managed[name]
+		if ignored[name] && !ok {
+			continue
+		}
+		if ok && equivalentExtAttr(local.(string), ea) {
+			out[name] = local
+		} else {
+			out[name] = ea.String()
+		}
+	}
+	return out
+}
+
+// equivalentExtAttr reports whether a value from the configuration is the one infoblox holds,
+// reading it as the JSON type infoblox returned rather than guessing from the text
+func equivalentExtAttr(local string, ea infoblox.ExtAttr) bool {
+	remote, ok := ea.Value.([]interface{})
+	if !ok {
+		return sameExtAttrValue(local, ea.Value)
+	}
+	list, ok := extAttrList(local)
+	if !ok {
+		list = []interface{}{local}
+	}
+	if len(list) != len(remote) {
+		return false
+	}
+	for i := range list {
+		if !sameExtAttrValue(extAttrText(list[i]), remote[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameExtAttrValue compares a single value as text with one read from infoblox
+func sameExtAttrValue(local string, remote interface{}) bool {
+	if n, ok := remote.(float64); ok {
+		v, err := strconv.ParseFloat(strings.TrimSpace(local), 64)
+		return err == nil && v == n
+	}
+	return local == (infoblox.ExtAttr{Value: remote}).String()
+}
+
+// extAttrsChanges returns the extattrs+ and extattrs- values of an update. Only attributes
+// terraform manages are removed, so those set outside of terraform are left alone.
+func extAttrsChanges(ctx context.Context, d *schema.ResourceData, m interface{}) (map[string]infoblox.ExtAttr, map[string]struct{}, error) {
+	o, n := d.GetChange("extattrs")
+	add, err := expandExtAttrs(ctx, m, n.(map[string]interface{}))
+	if err != nil {
+		return nil, nil, err
+	}
+	remove := map[string]struct{}{}
+	for name := range o.(map[string]interface{}) {
+		if _, ok := add[name]; !ok {
+			remove[name] = struct{}{}
+		}
+	}
+	return add, remove, nil
+}
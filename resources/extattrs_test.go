@@ -0,0 +1,68 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+func TestExtAttrValue(t *testing.T) {
+	str := infoblox.ExtAttrDef{Name: "CostCentre", Type: "STRING"}
+	strs := infoblox.ExtAttrDef{Name: "Environment", Type: "STRING", Flags: "M"}
+	num := infoblox.ExtAttrDef{Name: "Rack", Type: infoblox.ExtAttrTypeInteger}
+	nums := infoblox.ExtAttrDef{Name: "Ports", Type: infoblox.ExtAttrTypeInteger, Flags: "M"}
+
+	tests := map[string]struct {
+		def   infoblox.ExtAttrDef
+		value string
+		want  interface{}
+	}{
+		"numeric text":          {str, "1234", "1234"},
+		"list text":             {str, `["x"]`, `["x"]`},
+		"integer":               {num, "12", int64(12)},
+		"list of strings":       {strs, `["dev","test"]`, []interface{}{"dev", "test"}},
+		"single of multiple":    {strs, "dev", "dev"},
+		"numbers as strings":    {strs, `[1,2]`, []interface{}{"1", "2"}},
+		"list of integers":      {nums, `[80,"443"]`, []interface{}{int64(80), int64(443)}},
+		"integer of multiple":   {nums, "80", int64(80)},
+		"spaces around integer": {num, " 12 ", int64(12)},
+	}
+	for name, tc := range tests {
+		got, err := extAttrValue(tc.def, tc.value)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+		} else if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %#v, want %#v", name, got, tc.want)
+		}
+	}
+
+	for _, value := range []string{"twelve", "1.5", `["80","http"]`} {
+		if _, err := extAttrValue(nums, value); err == nil {
+			t.Errorf("expected %q to be rejected for an INTEGER attribute", value)
+		}
+	}
+}
+
+func TestEquivalentExtAttr(t *testing.T) {
+	tests := map[string]struct {
+		local  string
+		remote interface{}
+		want   bool
+	}{
+		"same text":            {"1234", "1234", true},
+		"text isn't trimmed":   {"1234 ", "1234", false},
+		"text isn't a list":    {`[ "x" ]`, `["x"]`, false},
+		"integer":              {"012", 12.0, true},
+		"other integer":        {"13", 12.0, false},
+		"list spacing":         {`[ "dev", "test" ]`, []interface{}{"dev", "test"}, true},
+		"list order":           {`["test","dev"]`, []interface{}{"dev", "test"}, false},
+		"integers as strings":  {`["80","443"]`, []interface{}{80.0, 443.0}, true},
+		"single value of list": {"dev", []interface{}{"dev"}, true},
+	}
+	for name, tc := range tests {
+		if got := equivalentExtAttr(tc.local, infoblox.ExtAttr{Value: tc.remote}); got != tc.want {
+			t.Errorf("%s: got %t, want %t", name, got, tc.want)
+		}
+	}
+}
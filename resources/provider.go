@@ -1,7 +1,13 @@
 package resources
 
 import (
-	"github.com/hashicorp/terraform/helper/schema"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hiscox/terraform-provider-infoblox/infoblox"
 )
 
@@ -43,7 +49,116 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeInt,
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("INFOBLOX_TIMEOUT", 30),
-				Description: "Request timeout",
+				Description: "Timeout in seconds for each HTTP request to infoblox, resource timeouts bound whole operations",
+			},
+			"ca_cert_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("INFOBLOX_CA_CERT_FILE", nil),
+				Description: "Path to a PEM file of CA certificates trusted for the infoblox certificate, in addition to the system ones",
+			},
+			"ca_cert_pem": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("INFOBLOX_CA_CERT_PEM", nil),
+				Description: "PEM encoded CA certificates trusted for the infoblox certificate, in addition to the system ones",
+			},
+			"client_cert": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("INFOBLOX_CLIENT_CERT", nil),
+				RequiredWith: []string{"client_key"},
+				Description:  "Client certificate for mutual TLS, as PEM or a path to a PEM file",
+			},
+			"client_key": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				DefaultFunc:  schema.EnvDefaultFunc("INFOBLOX_CLIENT_KEY", nil),
+				RequiredWith: []string{"client_cert"},
+				Description:  "Private key of the client certificate, as PEM or a path to a PEM file",
+			},
+			"min_tls_version": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INFOBLOX_MIN_TLS_VERSION", nil),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(infoblox.TLSVersions(), false)),
+				Description:      "Lowest TLS version accepted from infoblox: 1.0, 1.1, 1.2 or 1.3",
+			},
+			"on_conflict": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INFOBLOX_ON_CONFLICT", onConflictFail),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(onConflictPolicies, false)),
+				Description:      "What to do when a record being created already exists: fail, adopt or overwrite",
+			},
+			"max_retries": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INFOBLOX_MAX_RETRIES", 3),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description:      "How many times a request failing with a transient error is retried, 0 disables retries",
+			},
+			"retry_wait_min": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INFOBLOX_RETRY_WAIT_MIN", 1),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "Seconds to wait before the first retry, doubling for each retry after",
+			},
+			"retry_wait_max": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INFOBLOX_RETRY_WAIT_MAX", 30),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "Most seconds to wait between retries, unless infoblox asks for longer with Retry-After",
+			},
+			"retry_status_codes": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "HTTP status codes treated as transient, defaults to 429, 502, 503 and 504",
+			},
+			"max_concurrent_requests": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INFOBLOX_MAX_CONCURRENT_REQUESTS", 0),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description:      "Most requests sent to infoblox at the same time across all resources, 0 is unlimited",
+			},
+			"requests_per_second": &schema.Schema{
+				Type:             schema.TypeFloat,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INFOBLOX_REQUESTS_PER_SECOND", 0.0),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.FloatAtLeast(0)),
+				Description:      "Most requests started per second across all resources, 0 is unlimited",
+			},
+			"batch_window_ms": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("INFOBLOX_BATCH_WINDOW_MS", 0),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description:      "Milliseconds to wait for other creates, updates and deletes to send with each one as a single request, 0 disables batching",
+			},
+			"max_batch_size": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          infoblox.DefaultMaxBatchSize,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "Most writes sent in a single batched request",
+			},
+			"page_size": &schema.Schema{
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          infoblox.DefaultPageSize,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "How many records each search request fetches from infoblox, larger results are paged",
+			},
+			"ignore_extattrs": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Extensible attributes managed outside of terraform, left out of every record's extattrs unless set in its configuration",
 			},
 		},
 
@@ -51,13 +166,29 @@ func Provider() *schema.Provider {
 			"infoblox_a_record":     resourceARecord(),
 			"infoblox_txt_record":   resourceTxtRecord(),
 			"infoblox_cname_record": resourceCnameRecord(),
+			"infoblox_aaaa_record":  resourceAaaaRecord(),
+			"infoblox_ptr_record":   resourcePtrRecord(),
+			"infoblox_host_record":  resourceHostRecord(),
+			"infoblox_mx_record":    resourceMxRecord(),
+			"infoblox_srv_record":   resourceSrvRecord(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+// providerMeta is shared by every resource of a configured provider
+type providerMeta struct {
+	client         *infoblox.Client
+	onConflict     string
+	ignoreExtAttrs map[string]bool
+
+	// mu guards defs, the extensible attribute definitions read from infoblox so far
+	mu   sync.Mutex
+	defs map[string]infoblox.ExtAttrDef
+}
+
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 
 	params := infoblox.Cfg{
 		Host:        d.Get("host").(string),
@@ -66,17 +197,48 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		Password:    d.Get("password").(string),
 		TLSVerify:   d.Get("tls_verify").(bool),
 		Timeout:     d.Get("timeout").(int),
+
+		CACertFile:    d.Get("ca_cert_file").(string),
+		CACertPEM:     d.Get("ca_cert_pem").(string),
+		ClientCert:    d.Get("client_cert").(string),
+		ClientKey:     d.Get("client_key").(string),
+		MinTLSVersion: d.Get("min_tls_version").(string),
+
+		Retry: infoblox.RetryPolicy{
+			MaxAttempts: d.Get("max_retries").(int) + 1,
+			MinBackoff:  time.Duration(d.Get("retry_wait_min").(int)) * time.Second,
+			MaxBackoff:  time.Duration(d.Get("retry_wait_max").(int)) * time.Second,
+		},
+	}
+	params.MaxConcurrentRequests = d.Get("max_concurrent_requests").(int)
+	params.RequestsPerSecond = d.Get("requests_per_second").(float64)
+	params.BatchWindow = time.Duration(d.Get("batch_window_ms").(int)) * time.Millisecond
+	params.MaxBatchSize = d.Get("max_batch_size").(int)
+	params.PageSize = d.Get("page_size").(int)
+	for _, code := range d.Get("retry_status_codes").([]interface{}) {
+		params.Retry.RetryableStatusCodes = append(params.Retry.RetryableStatusCodes, code.(int))
 	}
 
 	client, err := infoblox.ClientInit(&params)
 	if err != nil {
-		return nil, err
+		return nil, diag.FromErr(err)
 	}
 
-	err = infoblox.IbGetTest(client)
+	err = client.IbGetTest(ctx)
+	if infoblox.IsUnauthorized(err) {
+		return nil, attrError("username", err)
+	}
 	if err != nil {
-		return nil, err
+		return nil, diag.FromErr(err)
 	}
 
-	return client, nil
+	meta := &providerMeta{
+		client:         client,
+		onConflict:     d.Get("on_conflict").(string),
+		ignoreExtAttrs: map[string]bool{},
+	}
+	for _, name := range d.Get("ignore_extattrs").(*schema.Set).List() {
+		meta.ignoreExtAttrs[name.(string)] = true
+	}
+	return meta, nil
 }
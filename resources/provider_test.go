@@ -0,0 +1,159 @@
+package resources
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox/wapitest"
+)
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+// testAccProviderFactories hands the test harness a fresh provider for each acceptance test
+func testAccProviderFactories() map[string]func() (*schema.Provider, error) {
+	return map[string]func() (*schema.Provider, error){
+		"infoblox": func() (*schema.Provider, error) {
+			return Provider(), nil
+		},
+	}
+}
+
+// testAccServer starts a stand-in WAPI for one acceptance test, so the suite never needs a real grid
+func testAccServer(t *testing.T) *wapitest.Server {
+	t.Helper()
+	s := wapitest.NewServer()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// testAccProviderConfig points the provider at the stand-in WAPI
+func testAccProviderConfig(s *wapitest.Server) string {
+	return testAccProviderConfigWith(s, "")
+}
+
+// testAccProviderConfigWith points the provider at the stand-in WAPI with extra provider settings
+func testAccProviderConfigWith(s *wapitest.Server, extra string) string {
+	return fmt.Sprintf(`
+provider "infoblox" {
+  host         = %q
+  username     = %q
+  password     = %q
+  wapi_version = %q
+  tls_verify   = false
+  timeout      = 10
+%s
+}
+`, s.Host(), s.Username, s.Password, s.WAPIVersion, extra)
+}
+
+// testAccCheckBatched checks writes reached the WAPI as multi-object requests
+func testAccCheckBatched(s *wapitest.Server) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if s.Batches() == 0 {
+			return fmt.Errorf("expected writes to be batched")
+		}
+		return nil
+	}
+}
+
+// testAccCheckRecordExists checks the resource's ID is the _ref of a record held by the WAPI
+// and stores it in ref so later steps can compare
+func testAccCheckRecordExists(s *wapitest.Server, n string, ref *string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		if s.Get(rs.Primary.ID) == nil {
+			return fmt.Errorf("%s has ID %s which doesn't exist in infoblox", n, rs.Primary.ID)
+		}
+		*ref = rs.Primary.ID
+		return nil
+	}
+}
+
+// testAccCheckRecordDestroyed checks no records of the given type are left behind
+func testAccCheckRecordDestroyed(s *wapitest.Server, objType string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if objs := s.Objects(objType); len(objs) != 0 {
+			return fmt.Errorf("%d %s still exist after destroy: %v", len(objs), objType, objs)
+		}
+		return nil
+	}
+}
+
+// testAccCheckRecordRemote checks a field of the record held by the WAPI
+func testAccCheckRecordRemote(s *wapitest.Server, ref *string, field string, want interface{}) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		obj := s.Get(*ref)
+		if obj == nil {
+			return fmt.Errorf("%s doesn't exist in infoblox", *ref)
+		}
+		if fmt.Sprint(obj[field]) != fmt.Sprint(want) {
+			return fmt.Errorf("%s %s is %v in infoblox, want %v", *ref, field, obj[field], want)
+		}
+		return nil
+	}
+}
+
+// testAccCheckExtAttrRemote checks an extensible attribute of the record held by the WAPI, along with the JSON type
+// of its value. A nil want checks it isn't set.
+func testAccCheckExtAttrRemote(s *wapitest.Server, ref *string, name string, want interface{}) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		obj := s.Get(*ref)
+		if obj == nil {
+			return fmt.Errorf("%s doesn't exist in infoblox", *ref)
+		}
+		eas, _ := obj["extattrs"].(map[string]interface{})
+		ea, ok := eas[name].(map[string]interface{})
+		switch {
+		case want == nil && ok:
+			return fmt.Errorf("%s extensible attribute %s is %v in infoblox, want it unset", *ref, name, ea["value"])
+		case want == nil:
+			return nil
+		case !ok:
+			return fmt.Errorf("%s extensible attribute %s isn't set in infoblox", *ref, name)
+		case !reflect.DeepEqual(ea["value"], want):
+			return fmt.Errorf("%s extensible attribute %s is %v in infoblox, want %v", *ref, name, ea["value"], want)
+		}
+		return nil
+	}
+}
+
+// testAccDefineExtAttr defines an extensible attribute in the WAPI, flags holding M allows multiple values
+func testAccDefineExtAttr(t *testing.T, s *wapitest.Server, name string, eaType string, flags string) {
+	t.Helper()
+	if _, err := s.Create("extensibleattributedef", map[string]interface{}{"name": name, "type": eaType, "flags": flags}); err != nil {
+		t.Fatalf("defining extensible attribute %s: %s", name, err)
+	}
+}
+
+// testAccCheckRefChanged checks the resource was replaced rather than updated in place
+func testAccCheckRefChanged(s *wapitest.Server, old *string, current *string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		if *old == *current {
+			return fmt.Errorf("expected %s to be replaced", *old)
+		}
+		if s.Get(*old) != nil {
+			return fmt.Errorf("replaced record %s still exists in infoblox", *old)
+		}
+		return nil
+	}
+}
+
+// testAccEditRemote simulates someone editing a record by hand between steps
+func testAccEditRemote(t *testing.T, s *wapitest.Server, ref *string, fields map[string]interface{}) func() {
+	return func() {
+		if _, err := s.Update(*ref, fields); err != nil {
+			t.Fatalf("editing %s out of band: %s", *ref, err)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+const (
+	onConflictFail      = "fail"
+	onConflictAdopt     = "adopt"
+	onConflictOverwrite = "overwrite"
+)
+
+var onConflictPolicies = []string{onConflictFail, onConflictAdopt, onConflictOverwrite}
+
+// onConflictSchema lets a resource override the provider on_conflict policy
+func onConflictSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(onConflictPolicies, false)),
+		Description:      "What to do when the record already exists on create: fail, adopt or overwrite. Defaults to the provider setting",
+	}
+}
+
+// recordTimeouts bounds each whole operation on a record, including every request and retry it makes.
+// The provider timeout setting separately limits each individual HTTP request.
+func recordTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(5 * time.Minute),
+		Read:   schema.DefaultTimeout(2 * time.Minute),
+		Update: schema.DefaultTimeout(5 * time.Minute),
+		Delete: schema.DefaultTimeout(5 * time.Minute),
+	}
+}
+
+// createRecord creates a record unless one matching the identifying fields already exists, in which
+// case the on_conflict policy decides whether to fail, adopt the record as is or overwrite it with body.
+// bodyUp is used for the overwrite as it must leave out fields infoblox can't update.
+func createRecord(ctx context.Context, d *schema.ResourceData, m interface{}, rcdType string, identity map[string]string, body map[string]interface{}, bodyUp map[string]interface{}) diag.Diagnostics {
+	meta := m.(*providerMeta)
+	client := meta.client
+
+	extattrs, err := expandExtAttrs(ctx, m, d.Get("extattrs").(map[string]interface{}))
+	if err != nil {
+		return attrError("extattrs", err)
+	}
+	// the overwrite keeps extensible attributes set outside of terraform
+	body["extattrs"] = extattrs
+	bodyUp["extattrs+"] = extattrs
+
+	log.Printf("Does remote record:%s matching %v exist ?", rcdType, identity)
+	i, err := client.IbFindRecord(ctx, rcdType, identity)
+	if infoblox.IsNotFound(err) {
+		log.Printf("Creating record:%s %s", rcdType, identity["name"])
+		ref, err := client.IbCreateRecord(ctx, rcdType, recordJSON(body))
+		if infoblox.IsConflict(err) {
+			return attrError("name", fmt.Errorf("record:%s %s was created outside of terraform while being created: %w", rcdType, identity["name"], err))
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(ref)
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	policy := meta.onConflict
+	if v, ok := d.GetOk("on_conflict"); ok {
+		policy = v.(string)
+	}
+	switch policy {
+	case onConflictAdopt:
+		log.Printf("Adopting existing record:%s %s", rcdType, i.Ref)
+		d.SetId(i.Ref)
+	case onConflictOverwrite:
+		log.Printf("Overwriting existing record:%s %s", rcdType, i.Ref)
+		ref, err := client.IbUpdateRecord(ctx, i.Ref, recordJSON(bodyUp))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(ref)
+	default:
+		return attrError("name", fmt.Errorf("record:%s %s already exists in infoblox as %s, set on_conflict to adopt or overwrite to manage it with terraform", rcdType, identity["name"], i.Ref))
+	}
+	return nil
+}
+
+// readRecord refreshes state from the record in infoblox, clearing the ID when it no longer exists.
+// setState copies the fields of the record type into state.
+func readRecord(ctx context.Context, d *schema.ResourceData, m interface{}, setState func(*schema.ResourceData, interface{}, infoblox.Result) diag.Diagnostics) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	log.Printf("Retrieving remote record %s", d.Id())
+	i, err := client.IbReadRecordByRef(ctx, d.Id())
+	if infoblox.IsNotFound(err) {
+		log.Printf("Resource not found")
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return setState(d, m, i)
+}
+
+// updateRecord sends fields and the common fields to infoblox when any of them or the given keys changed
+func updateRecord(ctx context.Context, d *schema.ResourceData, m interface{}, rcdType string, keys []string, fields map[string]interface{}) diag.Diagnostics {
+	if !hasRecordChange(d, rcdType, keys...) {
+		return nil
+	}
+	client := m.(*providerMeta).client
+	body := recordUpdateBody(d, rcdType, fields)
+	add, remove, err := extAttrsChanges(ctx, d, m)
+	if err != nil {
+		return attrError("extattrs", err)
+	}
+	body["extattrs+"], body["extattrs-"] = add, remove
+
+	// note that view cannot be updated
+	ref, err := client.IbUpdateRecord(ctx, d.Id(), recordJSON(body))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// the _ref embeds the name so changes when the record is renamed
+	d.SetId(ref)
+	return nil
+}
+
+// deleteRecord removes the record from infoblox, one already deleted outside of terraform isn't an error
+func deleteRecord(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+
+	err := client.IbDeleteRecord(ctx, d.Id())
+	if infoblox.IsNotFound(err) {
+		log.Printf("Resource already deleted")
+		return nil
+	}
+	return diag.FromErr(err)
+}
+
+// recordStateUpgradeV0 moves state from the concatenated field IDs used before
+// schema version 1 to the WAPI _ref, looking the record up by the given identifying fields
+func recordStateUpgradeV0(rcdType string, keys ...string) schema.StateUpgradeFunc {
+	return func(ctx context.Context, rawState map[string]interface{}, m interface{}) (map[string]interface{}, error) {
+		client := m.(*providerMeta).client
+		fields := map[string]string{}
+		for _, k := range keys {
+			fields[k], _ = rawState[k].(string)
+		}
+
+		log.Printf("Migrating record:%s state to _ref ID", rcdType)
+		i, err := client.IbFindRecord(ctx, rcdType, fields)
+		if infoblox.IsNotFound(err) {
+			// leave the old ID in place, Read treats it as a missing record
+			log.Printf("Remote record:%s for %v not found during migration", rcdType, fields)
+			return rawState, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rawState["id"] = i.Ref
+		return rawState, nil
+	}
+}
+
+// recordImporter imports a record by its WAPI _ref or by view/name. For record types where
+// several records can share a name the identifying values can follow as view/name/value/...
+// in the order of valueKeys. set copies the fields of the record found into state.
+func recordImporter(rcdType string, set func(*schema.ResourceData, interface{}, infoblox.Result) diag.Diagnostics, valueKeys ...string) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			client := m.(*providerMeta).client
+			id := d.Id()
+
+			var i infoblox.Result
+			var err error
+			if refType, ok := infoblox.RefRecordType(id); ok {
+				if refType != rcdType {
+					return nil, fmt.Errorf("Cannot import %s as record:%s", id, rcdType)
+				}
+				log.Printf("Importing record:%s by _ref %s", rcdType, id)
+				i, err = client.IbReadRecordByRef(ctx, id)
+			} else {
+				i, err = findImportRecord(ctx, client, rcdType, valueKeys, id)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			d.SetId(i.Ref)
+			if diags := set(d, m, i); diags.HasError() {
+				return nil, fmt.Errorf("Error importing record:%s %s: %s", rcdType, id, diags[0].Summary)
+			}
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+// findImportRecord resolves a view/name[/value...] import ID to exactly one record, the values
+// being those of valueKeys in order. The last value may contain slashes.
+func findImportRecord(ctx context.Context, client *infoblox.Client, rcdType string, valueKeys []string, id string) (infoblox.Result, error) {
+	parts := strings.SplitN(id, "/", 2+len(valueKeys))
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		if len(valueKeys) == 0 {
+			return infoblox.Result{}, fmt.Errorf("Invalid import ID %q, expected a record:%s _ref or view/name", id, rcdType)
+		}
+		return infoblox.Result{}, fmt.Errorf("Invalid import ID %q, expected a record:%s _ref, view/name or view/name/%s", id, rcdType, strings.Join(valueKeys, "/"))
+	}
+	q := infoblox.NewQuery().View(parts[0]).Where("name", parts[1])
+	for n, v := range parts[2:] {
+		q.Where(valueKeys[n], v)
+	}
+
+	log.Printf("Importing record:%s matching %s", rcdType, q)
+	result, err := client.IbQueryRecords(ctx, rcdType, q)
+	if err != nil {
+		return infoblox.Result{}, err
+	}
+	switch len(result) {
+	case 0:
+		return infoblox.Result{}, fmt.Errorf("No record:%s found for import ID %q", rcdType, id)
+	case 1:
+		return result[0], nil
+	default:
+		return infoblox.Result{}, fmt.Errorf("%d record:%s found for import ID %q, import by view/name/%s or _ref instead", len(result), rcdType, id, strings.Join(valueKeys, "/"))
+	}
+}
+
+// setRecordFields writes the remote values of a record into state so terraform can plan
+// against them, logging each attribute that changed outside of terraform
+func setRecordFields(d *schema.ResourceData, rcdType string, fields map[string]interface{}) diag.Diagnostics {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		remote := fields[k]
+		if local, ok := d.GetOk(k); ok && valuesDiffer(local, remote) {
+			log.Printf("[INFO] record:%s %s drifted: %s is %#v in state but %#v in infoblox", rcdType, d.Id(), k, local, remote)
+		}
+		if err := d.Set(k, remote); err != nil {
+			return attrError(k, fmt.Errorf("Error setting %s for record:%s %s: %s", k, rcdType, d.Id(), err))
+		}
+	}
+	return nil
+}
+
+// valuesDiffer compares a value held in state with one read from infoblox. Sets are compared by
+// their elements, as two *schema.Set holding the same values aren't deeply equal.
+func valuesDiffer(local interface{}, remote interface{}) bool {
+	set, ok := local.(*schema.Set)
+	if !ok {
+		return !reflect.DeepEqual(local, remote)
+	}
+	switch r := remote.(type) {
+	case *schema.Set:
+		return !reflect.DeepEqual(set.List(), r.List())
+	case []interface{}:
+		return !reflect.DeepEqual(set.List(), schema.NewSet(set.F, r).List())
+	}
+	return true
+}
+
+// attrError reports err against a single attribute so terraform can point at it in the configuration
+func attrError(attr string, err error) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: cty.GetAttrPath(attr),
+		},
+	}
+}
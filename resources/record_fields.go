@@ -0,0 +1,149 @@
+package resources
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+// commonFields are the optional attributes every record resource shares
+var commonFields = []string{"comment", "disable", "ttl", "extattrs", "creator"}
+
+// withoutCreator lists the record types infoblox keeps no creator for
+var withoutCreator = map[string]bool{"host": true}
+
+// recordCreators are the values of creator terraform can set, SYSTEM records are made by infoblox itself
+var recordCreators = []string{"STATIC", "DYNAMIC"}
+
+// recordCommonFields returns the common fields a record type has
+func recordCommonFields(rcdType string) []string {
+	if !withoutCreator[rcdType] {
+		return commonFields
+	}
+	fields := make([]string, 0, len(commonFields))
+	for _, f := range commonFields {
+		if f != "creator" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// recordSchema adds the view, the common fields and on_conflict to the attributes of a record type
+func recordSchema(rcdType string, fields map[string]*schema.Schema) map[string]*schema.Schema {
+	fields["view"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		DefaultFunc: schema.EnvDefaultFunc("INFOBLOX_VIEW", nil),
+		Description: "Infoblox view, case sensitive",
+	}
+	fields["comment"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Comment shown against the record in infoblox",
+	}
+	fields["disable"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Description: "Keeps the record in infoblox without serving it",
+	}
+	fields["ttl"] = ttlSchema()
+	fields["extattrs"] = extAttrsSchema()
+	if !withoutCreator[rcdType] {
+		fields["creator"] = &schema.Schema{
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "STATIC",
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(recordCreators, false)),
+			Description:      "STATIC or DYNAMIC, dynamic records can be removed by DDNS updates",
+		}
+	}
+	fields["on_conflict"] = onConflictSchema()
+	return fields
+}
+
+// ttlSchema sets the record TTL, unset or 0 inherits the zone TTL
+func ttlSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeInt,
+		Optional:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+		Description:      "TTL of the record in seconds, unset or 0 inherits the zone TTL",
+	}
+}
+
+// recordTTL returns the ttl attribute for a record read from infoblox, 0 when it inherits the zone TTL
+func recordTTL(i infoblox.Result) int {
+	if !i.UseTTL {
+		return 0
+	}
+	return i.TTL
+}
+
+// hasRecordChange reports whether any of the given attributes or the common fields changed
+func hasRecordChange(d *schema.ResourceData, rcdType string, keys ...string) bool {
+	return d.HasChanges(append(keys, recordCommonFields(rcdType)...)...)
+}
+
+// commonFieldValues returns the common fields to send to infoblox, other than extattrs which
+// are written differently on create and update
+func commonFieldValues(d *schema.ResourceData, rcdType string) map[string]interface{} {
+	ttl := d.Get("ttl").(int)
+	values := map[string]interface{}{
+		"comment": d.Get("comment").(string),
+		"disable": d.Get("disable").(bool),
+		"ttl":     ttl,
+		"use_ttl": ttl > 0,
+	}
+	if !withoutCreator[rcdType] {
+		values["creator"] = d.Get("creator").(string)
+	}
+	return values
+}
+
+// recordCreateBodies returns the body creating a record from the fields of its type plus the view and
+// common fields, and the body overwriting an existing record with the same values. The overwrite leaves
+// out view as it can't be updated. createRecord adds the extensible attributes once it knows their types.
+func recordCreateBodies(d *schema.ResourceData, rcdType string, fields map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	create := commonFieldValues(d, rcdType)
+	update := commonFieldValues(d, rcdType)
+	for k, v := range fields {
+		create[k] = v
+		update[k] = v
+	}
+	create["view"] = d.Get("view").(string)
+	return create, update
+}
+
+// recordUpdateBody returns the body updating a record to the fields of its type plus the common fields.
+// view is left out as it can't be updated, updateRecord adds the changes to extensible attributes.
+func recordUpdateBody(d *schema.ResourceData, rcdType string, fields map[string]interface{}) map[string]interface{} {
+	body := commonFieldValues(d, rcdType)
+	for k, v := range fields {
+		body[k] = v
+	}
+	return body
+}
+
+// recordJSON encodes a request body, the values are all strings, numbers, bools and maps of them
+func recordJSON(body map[string]interface{}) []byte {
+	b, _ := json.Marshal(body)
+	return b
+}
+
+// setRecordState writes a record read from infoblox into state, the fields of its type plus the view and common fields
+func setRecordState(d *schema.ResourceData, m interface{}, rcdType string, i infoblox.Result, fields map[string]interface{}) diag.Diagnostics {
+	fields["view"] = i.View
+	fields["comment"] = i.Comment
+	fields["disable"] = i.Disable
+	fields["ttl"] = recordTTL(i)
+	fields["extattrs"] = flattenExtAttrs(i.ExtAttrs, m.(*providerMeta).ignoreExtAttrs, d.Get("extattrs").(map[string]interface{}))
+	if !withoutCreator[rcdType] {
+		fields["creator"] = i.Creator
+	}
+	return setRecordFields(d, rcdType, fields)
+}
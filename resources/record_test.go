@@ -0,0 +1,132 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox/wapitest"
+)
+
+func TestValuesDiffer(t *testing.T) {
+	addrs := func(ips ...string) *schema.Set {
+		blocks := make([]interface{}, len(ips))
+		for i, ip := range ips {
+			blocks[i] = map[string]interface{}{"ipv4addr": ip, "mac": "", "configure_for_dhcp": false}
+		}
+		return schema.NewSet(hostAddressHash, blocks)
+	}
+	aliases := func(names ...interface{}) *schema.Set {
+		return schema.NewSet(schema.HashString, names)
+	}
+
+	tests := map[string]struct {
+		local  interface{}
+		remote interface{}
+		want   bool
+	}{
+		"same string":         {"a", "a", false},
+		"different string":    {"a", "b", true},
+		"same set":            {aliases("a", "b"), aliases("b", "a"), false},
+		"different set":       {aliases("a", "b"), aliases("a"), true},
+		"same set as list":    {aliases("a", "b"), []interface{}{"b", "a"}, false},
+		"different list":      {aliases("a", "b"), []interface{}{"c"}, true},
+		"same address blocks": {addrs("10.0.0.1", "10.0.0.2"), addrs("10.0.0.2", "10.0.0.1"), false},
+		"changed address":     {addrs("10.0.0.1"), addrs("10.0.0.9"), true},
+	}
+	for name, tc := range tests {
+		if got := valuesDiffer(tc.local, tc.remote); got != tc.want {
+			t.Errorf("%s: got %t, want %t", name, got, tc.want)
+		}
+	}
+}
+
+func TestRecordStateUpgradeV0(t *testing.T) {
+	tests := map[string]struct {
+		resource *schema.Resource
+		objType  string
+		// fields are the attributes schema version 0 stored, which the record in infoblox also has
+		fields map[string]interface{}
+		// the ID schema version 0 gave the record
+		oldID string
+	}{
+		"a": {
+			resource: resourceARecord(),
+			objType:  "record:a",
+			fields:   map[string]interface{}{"ipv4addr": "10.0.0.1", "name": "host.example.com", "comment": "old", "view": "Internal"},
+			// ipv4addr + name + comment + view
+			oldID: "10.0.0.1" + "host.example.com" + "old" + "Internal",
+		},
+		"cname": {
+			resource: resourceCnameRecord(),
+			objType:  "record:cname",
+			fields:   map[string]interface{}{"name": "alias.example.com", "canonical": "host.example.com", "comment": "old", "view": "Internal"},
+			// name + canonical + comment + view
+			oldID: "alias.example.com" + "host.example.com" + "old" + "Internal",
+		},
+		"txt": {
+			resource: resourceTxtRecord(),
+			objType:  "record:txt",
+			// TXT records had no comment before schema version 1
+			fields: map[string]interface{}{"name": "example.com", "text": "v=spf1 -all", "view": "Internal"},
+			// name + text + view
+			oldID: "example.com" + "v=spf1 -all" + "Internal",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := testAccServer(t)
+			m := testProviderMeta(t, s)
+			upgrade := tc.resource.StateUpgraders[0].Upgrade
+			rawState := func() map[string]interface{} {
+				state := map[string]interface{}{"id": tc.oldID}
+				for k, v := range tc.fields {
+					state[k] = v
+				}
+				return state
+			}
+
+			ref, err := s.Create(tc.objType, tc.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			state, err := upgrade(context.Background(), rawState(), m)
+			if err != nil {
+				t.Fatalf("upgrading existing record: %s", err)
+			}
+			if state["id"] != ref {
+				t.Fatalf("expected the ID to become %s, got %v", ref, state["id"])
+			}
+
+			// a record deleted since is left to Read to drop from state
+			if err := s.Delete(ref); err != nil {
+				t.Fatal(err)
+			}
+			state, err = upgrade(context.Background(), rawState(), m)
+			if err != nil {
+				t.Fatalf("upgrading missing record: %s", err)
+			}
+			if state["id"] != tc.oldID {
+				t.Fatalf("expected the ID to stay %s, got %v", tc.oldID, state["id"])
+			}
+		})
+	}
+}
+
+// testProviderMeta configures the provider against the stand-in WAPI without terraform
+func testProviderMeta(t *testing.T, s *wapitest.Server) interface{} {
+	t.Helper()
+	p := Provider()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"host":         s.Host(),
+		"username":     s.Username,
+		"password":     s.Password,
+		"wapi_version": s.WAPIVersion,
+		"tls_verify":   false,
+	}))
+	if diags.HasError() {
+		t.Fatalf("configuring provider: %v", diags)
+	}
+	return p.Meta()
+}
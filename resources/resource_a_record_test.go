@@ -0,0 +1,404 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox/wapitest"
+)
+
+func TestAccARecord_basic(t *testing.T) {
+	s := testAccServer(t)
+	var ref, current string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:a"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccARecordConfig("10.0.0.1", "first", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_a_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "ipv4addr", "10.0.0.1"),
+					testAccCheckRecordRemote(s, &ref, "comment", "first"),
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "name", "host.example.com"),
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "view", "Internal"),
+				),
+			},
+			{
+				// update in place keeps the same record
+				Config: testAccProviderConfig(s) + testAccARecordConfig("10.0.0.2", "second", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_a_record.test", &current),
+					resource.TestCheckResourceAttrPtr("infoblox_a_record.test", "id", &ref),
+					testAccCheckRecordRemote(s, &ref, "ipv4addr", "10.0.0.2"),
+					testAccCheckRecordRemote(s, &ref, "comment", "second"),
+				),
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccARecordConfig("10.0.0.2", "second", "Internal"),
+				ResourceName:            "infoblox_a_record.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccARecordConfig("10.0.0.2", "second", "Internal"),
+				ResourceName:            "infoblox_a_record.test",
+				ImportState:             true,
+				ImportStateId:           "Internal/host.example.com/10.0.0.2",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				// changing view replaces the record
+				Config: testAccProviderConfig(s) + testAccARecordConfig("10.0.0.2", "second", "External"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_a_record.test", &current),
+					testAccCheckRefChanged(s, &ref, &current),
+					testAccCheckRecordRemote(s, &current, "view", "External"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccARecord_drift(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+	config := testAccProviderConfig(s) + testAccARecordConfig("10.0.0.1", "managed", "Internal")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:a"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckRecordExists(s, "infoblox_a_record.test", &ref),
+			},
+			{
+				PreConfig:          testAccEditRemote(t, s, &ref, map[string]interface{}{"comment": "edited by hand"}),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordRemote(s, &ref, "comment", "managed"),
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "comment", "managed"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccARecord_batched(t *testing.T) {
+	s := testAccServer(t)
+	config := testAccProviderConfigWith(s, "  batch_window_ms = 500") + `
+resource "infoblox_a_record" "test" {
+  count    = 5
+  ipv4addr = "10.0.0.${count.index + 1}"
+  name     = "host${count.index}.example.com"
+  comment  = "batched"
+  view     = "Internal"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:a"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBatched(s),
+					resource.TestCheckResourceAttr("infoblox_a_record.test.4", "ipv4addr", "10.0.0.5"),
+					func(*terraform.State) error {
+						if got := len(s.Objects("record:a")); got != 5 {
+							return fmt.Errorf("expected 5 records in infoblox, got %d", got)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccARecord_extattrs(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+	testAccDefineExtAttr(t, s, "Environment", "STRING", "M")
+	testAccDefineExtAttr(t, s, "CostCentre", "STRING", "")
+	testAccDefineExtAttr(t, s, "Rack", "INTEGER", "")
+	testAccDefineExtAttr(t, s, "Notes", "STRING", "")
+	provider := testAccProviderConfigWith(s, `  ignore_extattrs = ["Managed"]`)
+	first := provider + testAccARecordExtAttrsConfig(`
+    Owner       = "team-a"
+    Environment = jsonencode(["dev", "test"])`)
+	second := provider + testAccARecordExtAttrsConfig(`
+    Owner      = "team-b"
+    CostCentre = "1234"
+    Rack       = "12"
+    Notes      = jsonencode(["not", "a", "list"])`)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:a"),
+		Steps: []resource.TestStep{
+			{
+				Config: first,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_a_record.test", &ref),
+					testAccCheckExtAttrRemote(s, &ref, "Owner", "team-a"),
+					testAccCheckExtAttrRemote(s, &ref, "Environment", []interface{}{"dev", "test"}),
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "extattrs.Environment", `["dev","test"]`),
+				),
+			},
+			{
+				// attributes set by hand are drift unless the provider ignores them
+				PreConfig: testAccEditRemote(t, s, &ref, map[string]interface{}{"extattrs+": map[string]interface{}{
+					"Managed": map[string]interface{}{"value": "cmdb"},
+				}}),
+				Config:   first,
+				PlanOnly: true,
+			},
+			{
+				Config: second,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckExtAttrRemote(s, &ref, "Owner", "team-b"),
+					// values take the type of their definition, whatever they look like
+					testAccCheckExtAttrRemote(s, &ref, "CostCentre", "1234"),
+					testAccCheckExtAttrRemote(s, &ref, "Rack", 12.0),
+					testAccCheckExtAttrRemote(s, &ref, "Notes", `["not","a","list"]`),
+					testAccCheckExtAttrRemote(s, &ref, "Environment", nil),
+					testAccCheckExtAttrRemote(s, &ref, "Managed", "cmdb"),
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "extattrs.%", "4"),
+				),
+			},
+			{
+				// the integer reads back the same so there's nothing to change
+				Config:   second,
+				PlanOnly: true,
+			},
+			{
+				Config:                  second,
+				ResourceName:            "infoblox_a_record.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				PreConfig: testAccEditRemote(t, s, &ref, map[string]interface{}{"extattrs+": map[string]interface{}{
+					"Site": map[string]interface{}{"value": "London"},
+				}}),
+				Config:             second,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: provider + testAccARecordExtAttrsConfig(`
+    Rack = "twelve"`),
+				ExpectError: regexp.MustCompile(`Extensible attribute Rack is an INTEGER in infoblox but "twelve" isn't a whole number`),
+			},
+		},
+	})
+}
+
+func TestAccARecord_createTimeout(t *testing.T) {
+	s := testAccServer(t)
+	s.Latency = 100 * time.Millisecond
+	// waiting out every retry of the create would take over 20s, well past its timeout
+	s.FailMethod("POST", 20, 503, "1")
+	config := testAccProviderConfigWith(s, "  max_retries = 20") + `
+resource "infoblox_a_record" "test" {
+  ipv4addr = "10.0.0.1"
+  name     = "host.example.com"
+  view     = "Internal"
+
+  timeouts {
+    create = "2s"
+  }
+}
+`
+
+	start := time.Now()
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`returned 503`),
+			},
+		},
+	})
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("create gave up after %s rather than at its 2s timeout", elapsed)
+	}
+}
+
+func TestAccARecord_onConflictFail(t *testing.T) {
+	s := testAccServer(t)
+	existing := testAccCreateARecord(t, s)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(s) + testAccARecordOnConflictConfig(""),
+				ExpectError: regexp.MustCompile(`already exists in infoblox as ` + regexp.QuoteMeta(existing)),
+			},
+		},
+	})
+	testAccCheckRecordUntouched(t, s, existing)
+}
+
+func TestAccARecord_onConflictAdopt(t *testing.T) {
+	s := testAccServer(t)
+	existing := testAccCreateARecord(t, s)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:a"),
+		Steps: []resource.TestStep{
+			{
+				// the record is taken on as it is, so the next plan brings it in line with the configuration
+				Config: testAccProviderConfigWith(s, `  on_conflict = "adopt"`) + testAccARecordOnConflictConfig(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "id", existing),
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "comment", "by hand"),
+					testAccCheckRecordRemote(s, &existing, "comment", "by hand"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccProviderConfigWith(s, `  on_conflict = "adopt"`) + testAccARecordOnConflictConfig(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "id", existing),
+					testAccCheckRecordRemote(s, &existing, "comment", "managed"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccARecord_onConflictOverwrite(t *testing.T) {
+	s := testAccServer(t)
+	existing := testAccCreateARecord(t, s)
+	provider := testAccProviderConfigWith(s, `  on_conflict     = "overwrite"
+  ignore_extattrs = ["Site"]`)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:a"),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccARecordExtAttrsConfig(`
+    Owner = "team-a"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "id", existing),
+					testAccCheckRecordRemote(s, &existing, "comment", "tagged"),
+					testAccCheckExtAttrRemote(s, &existing, "Owner", "team-a"),
+					// extattrs+ leaves attributes set outside of terraform in place
+					testAccCheckExtAttrRemote(s, &existing, "Site", "London"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccARecord_onConflictOverride(t *testing.T) {
+	s := testAccServer(t)
+	existing := testAccCreateARecord(t, s)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:a"),
+		Steps: []resource.TestStep{
+			{
+				// the resource setting wins over adopting from the provider
+				Config:      testAccProviderConfigWith(s, `  on_conflict = "adopt"`) + testAccARecordOnConflictConfig("fail"),
+				ExpectError: regexp.MustCompile(`already exists in infoblox`),
+			},
+			{
+				// and over failing by default
+				Config: testAccProviderConfigWith(s, `  ignore_extattrs = ["Site"]`) + testAccARecordOnConflictConfig("overwrite"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("infoblox_a_record.test", "id", existing),
+					testAccCheckRecordRemote(s, &existing, "comment", "managed"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCreateARecord adds the record the on_conflict tests configure, as someone else would have by hand
+func testAccCreateARecord(t *testing.T, s *wapitest.Server) string {
+	ref, err := s.Create("record:a", map[string]interface{}{
+		"name":     "host.example.com",
+		"ipv4addr": "10.0.0.1",
+		"view":     "Internal",
+		"comment":  "by hand",
+		"extattrs": map[string]interface{}{"Site": map[string]interface{}{"value": "London"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+// testAccCheckRecordUntouched checks a record created by testAccCreateARecord wasn't changed or deleted
+func testAccCheckRecordUntouched(t *testing.T, s *wapitest.Server, ref string) {
+	obj := s.Get(ref)
+	if obj == nil {
+		t.Fatalf("%s was deleted", ref)
+	}
+	if obj["comment"] != "by hand" {
+		t.Fatalf("%s comment changed to %v", ref, obj["comment"])
+	}
+}
+
+func testAccARecordOnConflictConfig(onConflict string) string {
+	policy := ""
+	if onConflict != "" {
+		policy = fmt.Sprintf("\n  on_conflict = %q", onConflict)
+	}
+	return fmt.Sprintf(`
+resource "infoblox_a_record" "test" {
+  ipv4addr = "10.0.0.1"
+  name     = "host.example.com"
+  comment  = "managed"
+  view     = "Internal"%s
+}
+`, policy)
+}
+
+func testAccARecordExtAttrsConfig(extattrs string) string {
+	return fmt.Sprintf(`
+resource "infoblox_a_record" "test" {
+  ipv4addr = "10.0.0.1"
+  name     = "host.example.com"
+  comment  = "tagged"
+  view     = "Internal"
+
+  extattrs = {%s
+  }
+}
+`, extattrs)
+}
+
+func testAccARecordConfig(ipv4addr string, comment string, view string) string {
+	return fmt.Sprintf(`
+resource "infoblox_a_record" "test" {
+  ipv4addr = %q
+  name     = "host.example.com"
+  comment  = %q
+  view     = %q
+}
+`, ipv4addr, comment, view)
+}
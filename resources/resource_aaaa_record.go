@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+func resourceAaaaRecord() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAaaaRecordCreate,
+		ReadContext:   resourceAaaaRecordRead,
+		UpdateContext: resourceAaaaRecordUpdate,
+		DeleteContext: deleteRecord,
+		Importer:      recordImporter("aaaa", resourceAaaaRecordSetState, "ipv6addr"),
+
+		Timeouts: recordTimeouts(),
+
+		Schema: recordSchema("aaaa", map[string]*schema.Schema{
+			"ipv6addr": &schema.Schema{
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateIPv6),
+				DiffSuppressFunc: suppressEquivalentIP,
+				Description:      "IPv6 address, in any form as infoblox stores it compressed",
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		}),
+	}
+}
+
+func resourceAaaaRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	fields := resourceAaaaRecordFields(d)
+	body, bodyUp := recordCreateBodies(d, "aaaa", fields)
+
+	identity := map[string]string{"name": fields["name"].(string), "ipv6addr": fields["ipv6addr"].(string), "view": d.Get("view").(string)}
+	if diags := createRecord(ctx, d, m, "aaaa", identity, body, bodyUp); diags.HasError() {
+		return diags
+	}
+	return resourceAaaaRecordRead(ctx, d, m)
+}
+
+// resourceAaaaRecordFields returns the record:aaaa fields to send to infoblox, with the address
+// compressed so searches for it match the form infoblox stores
+func resourceAaaaRecordFields(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"ipv6addr": canonicalIP(d.Get("ipv6addr").(string)),
+		"name":     d.Get("name").(string),
+	}
+}
+
+// resourceAaaaRecordSetState copies a remote record into state
+func resourceAaaaRecordSetState(d *schema.ResourceData, m interface{}, i infoblox.Result) diag.Diagnostics {
+	return setRecordState(d, m, "aaaa", i, map[string]interface{}{
+		"ipv6addr": i.Ipv6addr,
+		"name":     i.Name,
+	})
+}
+
+func resourceAaaaRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return readRecord(ctx, d, m, resourceAaaaRecordSetState)
+}
+
+func resourceAaaaRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := updateRecord(ctx, d, m, "aaaa", []string{"ipv6addr", "name"}, resourceAaaaRecordFields(d)); diags.HasError() {
+		return diags
+	}
+	return resourceAaaaRecordRead(ctx, d, m)
+}
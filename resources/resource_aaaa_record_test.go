@@ -0,0 +1,126 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAaaaRecord_basic(t *testing.T) {
+	s := testAccServer(t)
+	var ref, current string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:aaaa"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccAaaaRecordConfig("2001:db8::1", "first", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_aaaa_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "ipv6addr", "2001:db8::1"),
+					testAccCheckRecordRemote(s, &ref, "comment", "first"),
+					resource.TestCheckResourceAttr("infoblox_aaaa_record.test", "name", "host.example.com"),
+					resource.TestCheckResourceAttr("infoblox_aaaa_record.test", "view", "Internal"),
+				),
+			},
+			{
+				// update in place keeps the same record
+				Config: testAccProviderConfig(s) + testAccAaaaRecordConfig("2001:db8::2", "second", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_aaaa_record.test", &current),
+					resource.TestCheckResourceAttrPtr("infoblox_aaaa_record.test", "id", &ref),
+					testAccCheckRecordRemote(s, &ref, "ipv6addr", "2001:db8::2"),
+					testAccCheckRecordRemote(s, &ref, "comment", "second"),
+				),
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccAaaaRecordConfig("2001:db8::2", "second", "Internal"),
+				ResourceName:            "infoblox_aaaa_record.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccAaaaRecordConfig("2001:db8::2", "second", "Internal"),
+				ResourceName:            "infoblox_aaaa_record.test",
+				ImportState:             true,
+				ImportStateId:           "Internal/host.example.com/2001:0db8:0000:0000:0000:0000:0000:0002",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				// changing view replaces the record
+				Config: testAccProviderConfig(s) + testAccAaaaRecordConfig("2001:db8::2", "second", "External"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_aaaa_record.test", &current),
+					testAccCheckRefChanged(s, &ref, &current),
+					testAccCheckRecordRemote(s, &current, "view", "External"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAaaaRecord_expandedAddress(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+	config := testAccProviderConfig(s) + testAccAaaaRecordConfig("2001:0db8:0000:0000:0000:0000:0000:0001", "expanded", "Internal")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:aaaa"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_aaaa_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "ipv6addr", "2001:db8::1"),
+					resource.TestCheckResourceAttr("infoblox_aaaa_record.test", "ipv6addr", "2001:db8::1"),
+				),
+			},
+			{
+				// the compressed address infoblox reports isn't a difference
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				PreConfig:          testAccEditRemote(t, s, &ref, map[string]interface{}{"ipv6addr": "2001:db8::99"}),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check:  testAccCheckRecordRemote(s, &ref, "ipv6addr", "2001:db8::1"),
+			},
+		},
+	})
+}
+
+func TestAccAaaaRecord_invalidAddress(t *testing.T) {
+	s := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(s) + testAccAaaaRecordConfig("10.0.0.1", "ipv4", "Internal"),
+				ExpectError: regexp.MustCompile(`expected ipv6addr to contain a valid IPv6 address`),
+			},
+		},
+	})
+}
+
+func testAccAaaaRecordConfig(ipv6addr string, comment string, view string) string {
+	return fmt.Sprintf(`
+resource "infoblox_aaaa_record" "test" {
+  ipv6addr = %q
+  name     = "host.example.com"
+  comment  = %q
+  view     = %q
+}
+`, ipv6addr, comment, view)
+}
@@ -1,21 +1,48 @@
 package resources
 
 import (
-	"fmt"
-	"log"
+	"context"
 
-	"github.com/go-resty/resty/v2"
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hiscox/terraform-provider-infoblox/infoblox"
 )
 
 func resourceCnameRecord() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceCnameRecordCreate,
-		Read:   resourceCnameRecordRead,
-		Update: resourceCnameRecordUpdate,
-		Delete: resourceCnameRecordDelete,
+		CreateContext: resourceCnameRecordCreate,
+		ReadContext:   resourceCnameRecordRead,
+		UpdateContext: resourceCnameRecordUpdate,
+		DeleteContext: deleteRecord,
+		Importer:      recordImporter("cname", resourceCnameRecordSetState),
 
+		Timeouts: recordTimeouts(),
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCnameRecordV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: recordStateUpgradeV0("cname", "name", "view"),
+			},
+		},
+
+		Schema: recordSchema("cname", map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"canonical": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		}),
+	}
+}
+
+// resourceCnameRecordV0 is the schema used while the ID was built from the record fields
+func resourceCnameRecordV0() *schema.Resource {
+	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -30,141 +57,48 @@ func resourceCnameRecord() *schema.Resource {
 				Required: true,
 			},
 			"view": &schema.Schema{
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				DefaultFunc: schema.EnvDefaultFunc("INFOBLOX_VIEW", nil),
-				Description: "Infoblox view, case sensitive",
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
 			},
 		},
 	}
 }
 
-func resourceCnameRecordCreate(d *schema.ResourceData, m interface{}) error {
-	name := d.Get("name").(string)
-	canonical := d.Get("canonical").(string)
-	comment := d.Get("comment").(string)
-	view := d.Get("view").(string)
-	client := m.(*resty.Client)
-	body := []byte(fmt.Sprintf(`{"name":%q, "canonical":%q, "comment":%q, "view":%q}`, name, canonical, comment, view))
+func resourceCnameRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	fields := resourceCnameRecordFields(d)
+	body, bodyUp := recordCreateBodies(d, "cname", fields)
 
-	// this handles a record pre-existing to terraform being used
-	log.Printf("Does remote record:cname exist for %s ?", name)
-	r, i, err := infoblox.IbReadRecord(client, name, "cname")
-	if r == 404 {
-		log.Printf("Remote record:cname %s does not exist", name)
-		d.SetId("")
-		log.Printf("Creating record:cname %s", name)
-		r, err = infoblox.IbCreateRecord(client, "cname", body)
-		if err != nil {
-			return err
-		}
-		if r == 201 {
-			log.Printf("Setting state references...")
-			d.Set("name", name)
-			d.Set("canonical", canonical)
-			d.Set("comment", comment)
-			d.Set("view", view)
-			d.SetId(name + canonical + comment + view)
-		}
-		return resourceCnameRecordRead(d, m)
-	} else if r == 200 { // already exists, update local state and call update func
-		log.Printf("Record:cname %s already exists", name)
-		log.Printf("Updating remote...")
-		r, err = infoblox.IbUpdateRecord(client, i.Ref, body)
-		if err != nil {
-			return err
-		}
-		log.Printf("Updating local state...")
-		d.Set("name", i.Name)
-		d.Set("canonical", i.Canonical)
-		d.Set("comment", i.Comment)
-		d.Set("view", i.View)
-		d.SetId(name + canonical + comment + view)
-		return nil
+	identity := map[string]string{"name": fields["name"].(string), "view": d.Get("view").(string)}
+	if diags := createRecord(ctx, d, m, "cname", identity, body, bodyUp); diags.HasError() {
+		return diags
 	}
-	if err != nil {
-		return err
-	}
-	return resourceCnameRecordRead(d, m)
+	return resourceCnameRecordRead(ctx, d, m)
 }
 
-func resourceCnameRecordRead(d *schema.ResourceData, m interface{}) error {
-	name := d.Get("name").(string)
-	canonical := d.Get("canonical").(string)
-	comment := d.Get("comment").(string)
-	view := d.Get("view").(string)
-	client := m.(*resty.Client)
-
-	log.Printf("Retrieving remote record:cname for %s", name)
-	r, i, err := infoblox.IbReadRecord(client, name, "cname")
-	// 404 indicates resource doesn't exist
-	if r == 404 {
-		log.Printf("Resource not found")
-		d.SetId("")
-		return nil
-	}
-	if err != nil {
-		return err
+// resourceCnameRecordFields returns the record:cname fields to send to infoblox
+func resourceCnameRecordFields(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      d.Get("name").(string),
+		"canonical": d.Get("canonical").(string),
 	}
-	// remote state doesn't match local (manual updates to ib)
-	if name != i.Name || canonical != i.Canonical || comment != i.Comment || view != i.View {
-		log.Printf("Remote state doesn't match local")
-		log.Printf("Local:\n" + " " + name + " " + canonical + " " + comment + " " + view)
-		log.Printf("Remote:\n" + " " + i.Name + " " + i.Canonical + " " + i.Comment + " " + i.View)
-		return nil
-	}
-	return nil
 }
 
-func resourceCnameRecordUpdate(d *schema.ResourceData, m interface{}) error {
-	if d.HasChange("name") || d.HasChange("canonical") || d.HasChange("comment") {
-		name := d.Get("name").(string)
-		canonical := d.Get("canonical").(string)
-		comment := d.Get("comment").(string)
-		view := d.Get("view").(string)
-		client := m.(*resty.Client)
-		body := []byte(fmt.Sprintf(`{"name":%q, "canonical":%q, "comment":%q, "view":%q}`, name, canonical, comment, view))
-
-		// we need the _ref of the record to update it
-		r, i, err := infoblox.IbReadRecord(client, name, "cname")
-		if err != nil {
-			return err
-		}
-		// note that view cannot be updated
-		r, err = infoblox.IbUpdateRecord(client, i.Ref, body)
-		if err != nil {
-			return err
-		}
-		if r == 200 {
-			log.Printf("Setting state references...")
-			d.Set("name", name)
-			d.Set("canonical", canonical)
-			d.Set("comment", comment)
-			d.Set("view", view)
-			d.SetId(name + canonical + comment + view)
-			return nil
-		}
-	}
-	return resourceCnameRecordRead(d, m)
+// resourceCnameRecordSetState copies a remote record into state
+func resourceCnameRecordSetState(d *schema.ResourceData, m interface{}, i infoblox.Result) diag.Diagnostics {
+	return setRecordState(d, m, "cname", i, map[string]interface{}{
+		"name":      i.Name,
+		"canonical": i.Canonical,
+	})
 }
 
-func resourceCnameRecordDelete(d *schema.ResourceData, m interface{}) error {
-	name := d.Get("name").(string)
-	client := m.(*resty.Client)
-
-	// we need the _ref of the record to delete it
-	r, i, err := infoblox.IbReadRecord(client, name, "cname")
-	if err != nil {
-		return err
-	}
+func resourceCnameRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return readRecord(ctx, d, m, resourceCnameRecordSetState)
+}
 
-	r, err = infoblox.IbDeleteRecord(client, i.Ref)
-	if err != nil {
-		return err
-	}
-	if r == 200 {
-		return nil
+func resourceCnameRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := updateRecord(ctx, d, m, "cname", []string{"name", "canonical"}, resourceCnameRecordFields(d)); diags.HasError() {
+		return diags
 	}
-	return nil
+	return resourceCnameRecordRead(ctx, d, m)
 }
@@ -0,0 +1,166 @@
+package resources
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccCnameRecord_basic(t *testing.T) {
+	s := testAccServer(t)
+	var ref, current string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:cname"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCnameRecordConfig("web1.example.com", "first", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_cname_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "canonical", "web1.example.com"),
+					testAccCheckRecordRemote(s, &ref, "comment", "first"),
+					resource.TestCheckResourceAttr("infoblox_cname_record.test", "name", "alias.example.com"),
+					resource.TestCheckResourceAttr("infoblox_cname_record.test", "view", "Internal"),
+				),
+			},
+			{
+				// update in place keeps the same record
+				Config: testAccProviderConfig(s) + testAccCnameRecordConfig("web2.example.com", "second", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_cname_record.test", &current),
+					resource.TestCheckResourceAttrPtr("infoblox_cname_record.test", "id", &ref),
+					testAccCheckRecordRemote(s, &ref, "canonical", "web2.example.com"),
+					testAccCheckRecordRemote(s, &ref, "comment", "second"),
+				),
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccCnameRecordConfig("web2.example.com", "second", "Internal"),
+				ResourceName:            "infoblox_cname_record.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccCnameRecordConfig("web2.example.com", "second", "Internal"),
+				ResourceName:            "infoblox_cname_record.test",
+				ImportState:             true,
+				ImportStateId:           "Internal/alias.example.com",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				// changing view replaces the record
+				Config: testAccProviderConfig(s) + testAccCnameRecordConfig("web2.example.com", "second", "External"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_cname_record.test", &current),
+					testAccCheckRefChanged(s, &ref, &current),
+					testAccCheckRecordRemote(s, &current, "view", "External"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCnameRecord_drift(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+	config := testAccProviderConfig(s) + testAccCnameRecordConfig("web1.example.com", "managed", "Internal")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:cname"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckRecordExists(s, "infoblox_cname_record.test", &ref),
+			},
+			{
+				PreConfig:          testAccEditRemote(t, s, &ref, map[string]interface{}{"comment": "edited by hand"}),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordRemote(s, &ref, "comment", "managed"),
+					resource.TestCheckResourceAttr("infoblox_cname_record.test", "comment", "managed"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCnameRecord_ttl(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+	inherit := testAccProviderConfig(s) + testAccCnameRecordTTLConfig("")
+	ttl := testAccProviderConfig(s) + testAccCnameRecordTTLConfig("ttl = 300")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:cname"),
+		Steps: []resource.TestStep{
+			{
+				Config: inherit,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_cname_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "use_ttl", false),
+					resource.TestCheckResourceAttr("infoblox_cname_record.test", "ttl", "0"),
+				),
+			},
+			{
+				Config: ttl,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordRemote(s, &ref, "ttl", 300),
+					testAccCheckRecordRemote(s, &ref, "use_ttl", true),
+					resource.TestCheckResourceAttr("infoblox_cname_record.test", "ttl", "300"),
+				),
+			},
+			{
+				PreConfig:          testAccEditRemote(t, s, &ref, map[string]interface{}{"ttl": 600}),
+				Config:             ttl,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: inherit,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordRemote(s, &ref, "use_ttl", false),
+					resource.TestCheckResourceAttr("infoblox_cname_record.test", "ttl", "0"),
+				),
+			},
+			{
+				// a ttl left behind while inheriting isn't drift
+				PreConfig: testAccEditRemote(t, s, &ref, map[string]interface{}{"ttl": 900}),
+				Config:    inherit,
+				PlanOnly:  true,
+			},
+		},
+	})
+}
+
+func testAccCnameRecordTTLConfig(ttl string) string {
+	return fmt.Sprintf(`
+resource "infoblox_cname_record" "test" {
+  name      = "alias.example.com"
+  canonical = "host.example.com"
+  comment   = "ttl"
+  view      = "Internal"
+  %s
+}
+`, ttl)
+}
+
+func testAccCnameRecordConfig(canonical string, comment string, view string) string {
+	return fmt.Sprintf(`
+resource "infoblox_cname_record" "test" {
+  name      = "alias.example.com"
+  canonical = %q
+  comment   = %q
+  view      = %q
+}
+`, canonical, comment, view)
+}
@@ -0,0 +1,251 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+// hostAddressLists are the blocks holding the addresses of a host record, it needs at least one address
+var hostAddressLists = []string{"ipv4addrs", "ipv6addrs"}
+
+func resourceHostRecord() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceHostRecordCreate,
+		ReadContext:   resourceHostRecordRead,
+		UpdateContext: resourceHostRecordUpdate,
+		DeleteContext: deleteRecord,
+		Importer:      recordImporter("host", resourceHostRecordSetState),
+		CustomizeDiff: resourceHostRecordCustomizeDiff,
+
+		Timeouts: recordTimeouts(),
+
+		Schema: recordSchema("host", map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"configure_for_dns": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Creates the A, AAAA and PTR records of the host, otherwise it only holds DHCP reservations",
+			},
+			"aliases": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Other names of the host, served as CNAME records",
+			},
+			"ipv4addrs": &schema.Schema{
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: hostAddressLists,
+				Set:          hostAddressHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ipv4addr": &schema.Schema{
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPv4Address),
+						},
+						"mac": &schema.Schema{
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsMACAddress),
+							StateFunc:        func(v interface{}) string { return canonicalMAC(v.(string)) },
+							Description:      "MAC address of the interface, needed to configure the address for DHCP",
+						},
+						"configure_for_dhcp": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+			"ipv6addrs": &schema.Schema{
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: hostAddressLists,
+				Set:          hostAddressHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ipv6addr": &schema.Schema{
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validateIPv6),
+							StateFunc:        func(v interface{}) string { return canonicalIP(v.(string)) },
+						},
+						"duid": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "DUID of the DHCPv6 client, needed to configure the address for DHCP",
+						},
+						"configure_for_dhcp": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+// hostAddressClients are the fields identifying the DHCP client of each kind of address block
+var hostAddressClients = map[string]string{"ipv4addrs": "mac", "ipv6addrs": "duid"}
+
+// resourceHostRecordCustomizeDiff rejects at plan an address configured for DHCP without the client
+// it is reserved for, which infoblox would only refuse on apply
+func resourceHostRecordCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	cfg := d.GetRawConfig()
+	if cfg.IsNull() || !cfg.IsKnown() {
+		return nil
+	}
+	for _, list := range hostAddressLists {
+		blocks := cfg.GetAttr(list)
+		if blocks.IsNull() || !blocks.IsKnown() {
+			continue
+		}
+		client := hostAddressClients[list]
+		for it := blocks.ElementIterator(); it.Next(); {
+			_, block := it.Element()
+			if !block.IsKnown() {
+				continue
+			}
+			dhcp := block.GetAttr("configure_for_dhcp")
+			if dhcp.IsNull() || !dhcp.IsKnown() || dhcp.False() {
+				continue
+			}
+			if v, _, known := configString(block, client); known && v == "" {
+				addr, _, _ := configString(block, strings.TrimSuffix(list, "s"))
+				return fmt.Errorf("%s %s has configure_for_dhcp set so needs a %s", list, addr, client)
+			}
+		}
+	}
+	return nil
+}
+
+// hostAddressHash identifies an address block by its values in the form infoblox stores them,
+// so an address written differently in the configuration isn't a different block
+func hostAddressHash(v interface{}) int {
+	m := v.(map[string]interface{})
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		value := fmt.Sprint(m[k])
+		switch k {
+		case "ipv4addr", "ipv6addr":
+			value = canonicalIP(value)
+		case "mac":
+			value = canonicalMAC(value)
+		}
+		fmt.Fprintf(&b, "%s=%s;", k, value)
+	}
+	return schema.HashString(b.String())
+}
+
+func resourceHostRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	fields := resourceHostRecordFields(d)
+	body, bodyUp := recordCreateBodies(d, "host", fields)
+
+	identity := map[string]string{"name": fields["name"].(string), "view": d.Get("view").(string)}
+	if diags := createRecord(ctx, d, m, "host", identity, body, bodyUp); diags.HasError() {
+		return diags
+	}
+	return resourceHostRecordRead(ctx, d, m)
+}
+
+// resourceHostRecordFields returns the record:host fields to send to infoblox. Both address lists
+// are always sent as infoblox replaces the addresses of a host with the lists given.
+func resourceHostRecordFields(d *schema.ResourceData) map[string]interface{} {
+	aliases := []string{}
+	for _, a := range d.Get("aliases").(*schema.Set).List() {
+		aliases = append(aliases, a.(string))
+	}
+	sort.Strings(aliases)
+
+	return map[string]interface{}{
+		"name":              d.Get("name").(string),
+		"configure_for_dns": d.Get("configure_for_dns").(bool),
+		"aliases":           aliases,
+		"ipv4addrs":         expandHostAddresses(d.Get("ipv4addrs").(*schema.Set), "ipv4addr", "mac"),
+		"ipv6addrs":         expandHostAddresses(d.Get("ipv6addrs").(*schema.Set), "ipv6addr", "duid"),
+	}
+}
+
+// expandHostAddresses converts address blocks to the structs infoblox takes, leaving out an empty client identifier
+func expandHostAddresses(set *schema.Set, addrKey string, clientKey string) []infoblox.HostAddress {
+	addrs := []infoblox.HostAddress{}
+	for _, v := range set.List() {
+		block := v.(map[string]interface{})
+		addr := infoblox.HostAddress{
+			ConfigureForDHCP: block["configure_for_dhcp"].(bool),
+		}
+		ip := canonicalIP(block[addrKey].(string))
+		client := block[clientKey].(string)
+		if addrKey == "ipv4addr" {
+			addr.Ipv4addr, addr.Mac = ip, canonicalMAC(client)
+		} else {
+			addr.Ipv6addr, addr.Duid = ip, client
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// flattenHostAddresses converts the addresses of a host record read from infoblox to address blocks
+func flattenHostAddresses(addrs []infoblox.HostAddress, addrKey string, clientKey string) *schema.Set {
+	blocks := make([]interface{}, 0, len(addrs))
+	for _, addr := range addrs {
+		block := map[string]interface{}{
+			"configure_for_dhcp": addr.ConfigureForDHCP,
+		}
+		if addrKey == "ipv4addr" {
+			block[addrKey], block[clientKey] = addr.Ipv4addr, addr.Mac
+		} else {
+			block[addrKey], block[clientKey] = addr.Ipv6addr, addr.Duid
+		}
+		blocks = append(blocks, block)
+	}
+	return schema.NewSet(hostAddressHash, blocks)
+}
+
+// resourceHostRecordSetState copies a remote record into state
+func resourceHostRecordSetState(d *schema.ResourceData, m interface{}, i infoblox.Result) diag.Diagnostics {
+	aliases := make([]interface{}, len(i.Aliases))
+	for n, a := range i.Aliases {
+		aliases[n] = a
+	}
+	return setRecordState(d, m, "host", i, map[string]interface{}{
+		"name":              i.Name,
+		"configure_for_dns": i.ConfigureForDNS,
+		"aliases":           schema.NewSet(schema.HashString, aliases),
+		"ipv4addrs":         flattenHostAddresses(i.Ipv4addrs, "ipv4addr", "mac"),
+		"ipv6addrs":         flattenHostAddresses(i.Ipv6addrs, "ipv6addr", "duid"),
+	})
+}
+
+func resourceHostRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return readRecord(ctx, d, m, resourceHostRecordSetState)
+}
+
+func resourceHostRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := updateRecord(ctx, d, m, "host", []string{"name", "configure_for_dns", "aliases", "ipv4addrs", "ipv6addrs"}, resourceHostRecordFields(d)); diags.HasError() {
+		return diags
+	}
+	return resourceHostRecordRead(ctx, d, m)
+}
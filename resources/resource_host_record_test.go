@@ -0,0 +1,258 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox/wapitest"
+)
+
+const testAccHostRecordAddresses = `
+  ipv4addrs {
+    ipv4addr           = "10.0.0.1"
+    mac                = "00:0A:95:9D:68:16"
+    configure_for_dhcp = true
+  }
+  ipv4addrs {
+    ipv4addr = "10.0.0.2"
+  }
+  ipv6addrs {
+    ipv6addr = "2001:0db8:0000:0000:0000:0000:0000:0001"
+  }
+`
+
+func TestAccHostRecord_basic(t *testing.T) {
+	s := testAccServer(t)
+	var ref, current string
+	config := testAccProviderConfig(s) + testAccHostRecordConfig(testAccHostRecordAddresses, `["www.example.com"]`, "first", "Internal")
+	updated := testAccProviderConfig(s) + testAccHostRecordConfig(`
+  ipv4addrs {
+    ipv4addr = "10.0.0.2"
+  }
+`, `["www.example.com", "web.example.com"]`, "second", "Internal")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:host"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_host_record.test", &ref),
+					testAccCheckHostAddressesRemote(s, &ref, "ipv4addrs", "10.0.0.1/00:0a:95:9d:68:16/true", "10.0.0.2//false"),
+					testAccCheckHostAddressesRemote(s, &ref, "ipv6addrs", "2001:db8::1//false"),
+					testAccCheckRecordRemote(s, &ref, "aliases", []string{"www.example.com"}),
+					testAccCheckRecordRemote(s, &ref, "configure_for_dns", true),
+					testAccCheckRecordRemote(s, &ref, "comment", "first"),
+					resource.TestCheckResourceAttr("infoblox_host_record.test", "ipv4addrs.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("infoblox_host_record.test", "ipv4addrs.*", map[string]string{
+						"ipv4addr":           "10.0.0.1",
+						"mac":                "00:0a:95:9d:68:16",
+						"configure_for_dhcp": "true",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("infoblox_host_record.test", "ipv6addrs.*", map[string]string{
+						"ipv6addr": "2001:db8::1",
+					}),
+				),
+			},
+			{
+				// the forms infoblox stores addresses in aren't a difference
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				// update in place keeps the same record
+				Config: updated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_host_record.test", &current),
+					resource.TestCheckResourceAttrPtr("infoblox_host_record.test", "id", &ref),
+					testAccCheckHostAddressesRemote(s, &ref, "ipv4addrs", "10.0.0.2//false"),
+					testAccCheckHostAddressesRemote(s, &ref, "ipv6addrs"),
+					testAccCheckRecordRemote(s, &ref, "aliases", []string{"web.example.com", "www.example.com"}),
+					testAccCheckRecordRemote(s, &ref, "comment", "second"),
+				),
+			},
+			{
+				Config:                  updated,
+				ResourceName:            "infoblox_host_record.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				Config:                  updated,
+				ResourceName:            "infoblox_host_record.test",
+				ImportState:             true,
+				ImportStateId:           "Internal/host.example.com",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				// changing view replaces the record
+				Config: testAccProviderConfig(s) + testAccHostRecordConfig(`
+  ipv4addrs {
+    ipv4addr = "10.0.0.2"
+  }
+`, `[]`, "second", "External"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_host_record.test", &current),
+					testAccCheckRefChanged(s, &ref, &current),
+					testAccCheckRecordRemote(s, &current, "view", "External"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccHostRecord_drift(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+	config := testAccProviderConfig(s) + testAccHostRecordConfig(testAccHostRecordAddresses, `[]`, "managed", "Internal")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:host"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckRecordExists(s, "infoblox_host_record.test", &ref),
+			},
+			{
+				PreConfig: testAccEditRemote(t, s, &ref, map[string]interface{}{
+					"ipv4addrs": []interface{}{map[string]interface{}{"ipv4addr": "10.0.0.9"}},
+				}),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check:  testAccCheckHostAddressesRemote(s, &ref, "ipv4addrs", "10.0.0.1/00:0a:95:9d:68:16/true", "10.0.0.2//false"),
+			},
+		},
+	})
+}
+
+func TestAccHostRecord_dhcpOnly(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:host"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "infoblox_host_record" "test" {
+  name              = "printer.example.com"
+  configure_for_dns = false
+  view              = "Internal"
+
+  ipv4addrs {
+    ipv4addr           = "10.0.0.5"
+    mac                = "00:0a:95:9d:68:17"
+    configure_for_dhcp = true
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_host_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "configure_for_dns", false),
+					resource.TestCheckResourceAttr("infoblox_host_record.test", "configure_for_dns", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccHostRecord_noAddress(t *testing.T) {
+	s := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(s) + testAccHostRecordConfig("", `[]`, "empty", "Internal"),
+				ExpectError: regexp.MustCompile(`one of .ipv4addrs,ipv6addrs. must be specified`),
+			},
+		},
+	})
+}
+
+func TestAccHostRecord_dhcpNeedsClient(t *testing.T) {
+	s := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccHostRecordConfig(`
+  ipv4addrs {
+    ipv4addr           = "10.0.0.1"
+    configure_for_dhcp = true
+  }
+`, `[]`, "no mac", "Internal"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`ipv4addrs 10.0.0.1 has configure_for_dhcp set so needs a mac`),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccHostRecordConfig(`
+  ipv6addrs {
+    ipv6addr           = "2001:db8::1"
+    configure_for_dhcp = true
+  }
+`, `[]`, "no duid", "Internal"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`ipv6addrs 2001:db8::1 has configure_for_dhcp set so needs a duid`),
+			},
+		},
+	})
+	if n := len(s.Objects("record:host")); n != 0 {
+		t.Fatalf("%d record:host created, want none", n)
+	}
+}
+
+// testAccCheckHostAddressesRemote checks the addresses of a host record held by the WAPI, each given as address/client/configure_for_dhcp
+func testAccCheckHostAddressesRemote(s *wapitest.Server, ref *string, list string, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		obj := s.Get(*ref)
+		if obj == nil {
+			return fmt.Errorf("%s doesn't exist in infoblox", *ref)
+		}
+		addrs, _ := obj[list].([]interface{})
+		got := make([]string, 0, len(addrs))
+		for _, a := range addrs {
+			addr := a.(map[string]interface{})
+			ip, client := addr["ipv4addr"], addr["mac"]
+			if list == "ipv6addrs" {
+				ip, client = addr["ipv6addr"], addr["duid"]
+			}
+			if client == nil {
+				client = ""
+			}
+			got = append(got, fmt.Sprintf("%v/%v/%v", ip, client, addr["configure_for_dhcp"]))
+		}
+		sort.Strings(got)
+		sort.Strings(want)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			return fmt.Errorf("%s %s are %v in infoblox, want %v", *ref, list, got, want)
+		}
+		return nil
+	}
+}
+
+func testAccHostRecordConfig(addresses string, aliases string, comment string, view string) string {
+	return fmt.Sprintf(`
+resource "infoblox_host_record" "test" {
+  name    = "host.example.com"
+  aliases = %s
+  comment = %q
+  view    = %q
+%s}
+`, aliases, comment, view, addresses)
+}
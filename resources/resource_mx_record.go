@@ -0,0 +1,87 @@
+package resources
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+func resourceMxRecord() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceMxRecordCreate,
+		ReadContext:   resourceMxRecordRead,
+		UpdateContext: resourceMxRecordUpdate,
+		DeleteContext: deleteRecord,
+		Importer:      recordImporter("mx", resourceMxRecordSetState, "mail_exchanger", "preference"),
+
+		Timeouts: recordTimeouts(),
+
+		Schema: recordSchema("mx", map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"mail_exchanger": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Host accepting mail for the name",
+			},
+			"preference": &schema.Schema{
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(0, 65535)),
+				Description:      "Preference of the mail exchanger, lower values are tried first",
+			},
+		}),
+	}
+}
+
+func resourceMxRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	fields := resourceMxRecordFields(d)
+	body, bodyUp := recordCreateBodies(d, "mx", fields)
+
+	// several MX records share a name, one per exchanger and preference
+	identity := map[string]string{
+		"name":           fields["name"].(string),
+		"mail_exchanger": fields["mail_exchanger"].(string),
+		"preference":     strconv.Itoa(fields["preference"].(int)),
+		"view":           d.Get("view").(string),
+	}
+	if diags := createRecord(ctx, d, m, "mx", identity, body, bodyUp); diags.HasError() {
+		return diags
+	}
+	return resourceMxRecordRead(ctx, d, m)
+}
+
+// resourceMxRecordFields returns the record:mx fields to send to infoblox
+func resourceMxRecordFields(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"name":           d.Get("name").(string),
+		"mail_exchanger": d.Get("mail_exchanger").(string),
+		"preference":     d.Get("preference").(int),
+	}
+}
+
+// resourceMxRecordSetState copies a remote record into state
+func resourceMxRecordSetState(d *schema.ResourceData, m interface{}, i infoblox.Result) diag.Diagnostics {
+	return setRecordState(d, m, "mx", i, map[string]interface{}{
+		"name":           i.Name,
+		"mail_exchanger": i.MailExchanger,
+		"preference":     i.Preference,
+	})
+}
+
+func resourceMxRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return readRecord(ctx, d, m, resourceMxRecordSetState)
+}
+
+func resourceMxRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := updateRecord(ctx, d, m, "mx", []string{"name", "mail_exchanger", "preference"}, resourceMxRecordFields(d)); diags.HasError() {
+		return diags
+	}
+	return resourceMxRecordRead(ctx, d, m)
+}
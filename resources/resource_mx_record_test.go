@@ -0,0 +1,130 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccMxRecord_basic(t *testing.T) {
+	s := testAccServer(t)
+	var ref, current string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:mx"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccMxRecordConfig("test", "mail1.example.com", 10, "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_mx_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "mail_exchanger", "mail1.example.com"),
+					testAccCheckRecordRemote(s, &ref, "preference", 10),
+					resource.TestCheckResourceAttr("infoblox_mx_record.test", "name", "example.com"),
+					resource.TestCheckResourceAttr("infoblox_mx_record.test", "preference", "10"),
+				),
+			},
+			{
+				// update in place keeps the same record
+				Config: testAccProviderConfig(s) + testAccMxRecordConfig("test", "mail1.example.com", 20, "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_mx_record.test", &current),
+					resource.TestCheckResourceAttrPtr("infoblox_mx_record.test", "id", &ref),
+					testAccCheckRecordRemote(s, &ref, "preference", 20),
+				),
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccMxRecordConfig("test", "mail1.example.com", 20, "Internal"),
+				ResourceName:            "infoblox_mx_record.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccMxRecordConfig("test", "mail1.example.com", 20, "Internal"),
+				ResourceName:            "infoblox_mx_record.test",
+				ImportState:             true,
+				ImportStateId:           "Internal/example.com/mail1.example.com/20",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				// changing view replaces the record
+				Config: testAccProviderConfig(s) + testAccMxRecordConfig("test", "mail1.example.com", 20, "External"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_mx_record.test", &current),
+					testAccCheckRefChanged(s, &ref, &current),
+					testAccCheckRecordRemote(s, &current, "view", "External"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMxRecord_shareName(t *testing.T) {
+	s := testAccServer(t)
+	var primary, backup string
+	if _, err := s.Create("record:mx", map[string]interface{}{
+		"name": "example.com", "mail_exchanger": "mail1.example.com", "preference": 30, "view": "Internal",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	config := testAccProviderConfig(s) +
+		testAccMxRecordConfig("primary", "mail1.example.com", 10, "Internal") +
+		testAccMxRecordConfig("backup", "mail2.example.com", 20, "Internal")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				// records differing only in exchanger or preference aren't conflicts
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_mx_record.primary", &primary),
+					testAccCheckRecordExists(s, "infoblox_mx_record.backup", &backup),
+					testAccCheckRecordRemote(s, &primary, "mail_exchanger", "mail1.example.com"),
+					testAccCheckRecordRemote(s, &backup, "mail_exchanger", "mail2.example.com"),
+				),
+			},
+			{
+				Config:       config,
+				ResourceName: "infoblox_mx_record.backup",
+				ImportState:  true,
+				// the name alone matches all three records
+				ImportStateId: "Internal/example.com",
+				ExpectError:   regexp.MustCompile(`3 record:mx found for import ID`),
+			},
+			{
+				Config:      config + testAccMxRecordConfig("duplicate", "mail1.example.com", 30, "Internal"),
+				ExpectError: regexp.MustCompile(`already exists in infoblox`),
+			},
+		},
+	})
+}
+
+func TestAccMxRecord_preference(t *testing.T) {
+	s := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(s) + testAccMxRecordConfig("test", "mail1.example.com", 65536, "Internal"),
+				ExpectError: regexp.MustCompile(`expected preference to be in the range \(0 - 65535\)`),
+			},
+		},
+	})
+}
+
+func testAccMxRecordConfig(resourceName string, exchanger string, preference int, view string) string {
+	return fmt.Sprintf(`
+resource "infoblox_mx_record" %q {
+  name           = "example.com"
+  mail_exchanger = %q
+  preference     = %d
+  view           = %q
+}
+`, resourceName, exchanger, preference, view)
+}
@@ -0,0 +1,169 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+// ptrRecordTargets are the ways of saying which address a PTR record is for, exactly one must be set
+var ptrRecordTargets = []string{"ipv4addr", "ipv6addr", "name"}
+
+func resourcePtrRecord() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePtrRecordCreate,
+		ReadContext:   resourcePtrRecordRead,
+		UpdateContext: resourcePtrRecordUpdate,
+		DeleteContext: deleteRecord,
+		Importer:      recordImporter("ptr", resourcePtrRecordSetState, "ptrdname"),
+		CustomizeDiff: resourcePtrRecordCustomizeDiff,
+
+		Timeouts: recordTimeouts(),
+
+		Schema: recordSchema("ptr", map[string]*schema.Schema{
+			"ptrdname": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ipv4addr": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ExactlyOneOf:     ptrRecordTargets,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPv4Address),
+				Description:      "IPv4 address the record points back from, infoblox sets name to its in-addr.arpa name",
+			},
+			"ipv6addr": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ExactlyOneOf:     ptrRecordTargets,
+				ValidateDiagFunc: validation.ToDiagFunc(validateIPv6),
+				DiffSuppressFunc: suppressEquivalentIP,
+				Description:      "IPv6 address the record points back from, infoblox sets name to its ip6.arpa name",
+			},
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: ptrRecordTargets,
+				Description:  "Reverse name of the record, such as 9.13.168.192.in-addr.arpa",
+			},
+		}),
+	}
+}
+
+// resourcePtrRecordCustomizeDiff plans the reverse name of a record given by address, and leaves the
+// address to infoblox to work out for a record given by name
+func resourcePtrRecordCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	cfg := d.GetRawConfig()
+	if cfg.IsNull() || !cfg.IsKnown() {
+		return nil
+	}
+	for k, other := range map[string]string{"ipv4addr": "ipv6addr", "ipv6addr": "ipv4addr"} {
+		addr, set, known := configString(cfg, k)
+		if !set {
+			continue
+		}
+		// a record has one address, moving a record between families clears the old one
+		if d.Get(other).(string) != "" {
+			if err := d.SetNew(other, ""); err != nil {
+				return err
+			}
+		}
+		if !known {
+			return d.SetNewComputed("name")
+		}
+		name, err := infoblox.ReverseName(addr)
+		if err != nil {
+			return err
+		}
+		if name != d.Get("name").(string) {
+			return d.SetNew("name", name)
+		}
+		return nil
+	}
+	if d.Id() != "" && d.HasChange("name") {
+		if err := d.SetNewComputed("ipv4addr"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("ipv6addr")
+	}
+	return nil
+}
+
+// configString returns a string attribute as written in the configuration, whether it is set at
+// all and whether its value is known yet
+func configString(cfg cty.Value, k string) (string, bool, bool) {
+	v := cfg.GetAttr(k)
+	switch {
+	case v.IsNull():
+		return "", false, true
+	case !v.IsKnown():
+		return "", true, false
+	}
+	return v.AsString(), true, true
+}
+
+func resourcePtrRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	fields, name, err := resourcePtrRecordFields(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	body, bodyUp := recordCreateBodies(d, "ptr", fields)
+
+	identity := map[string]string{"name": name, "ptrdname": fields["ptrdname"].(string), "view": d.Get("view").(string)}
+	if diags := createRecord(ctx, d, m, "ptr", identity, body, bodyUp); diags.HasError() {
+		return diags
+	}
+	return resourcePtrRecordRead(ctx, d, m)
+}
+
+// resourcePtrRecordFields returns the record:ptr fields to send to infoblox, with whichever of the
+// address or the name is configured, and the reverse name the record will have
+func resourcePtrRecordFields(d *schema.ResourceData) (map[string]interface{}, string, error) {
+	fields := map[string]interface{}{
+		"ptrdname": d.Get("ptrdname").(string),
+	}
+	cfg := d.GetRawConfig()
+	for _, k := range []string{"ipv4addr", "ipv6addr"} {
+		if addr, set, _ := configString(cfg, k); set {
+			addr = canonicalIP(addr)
+			name, err := infoblox.ReverseName(addr)
+			fields[k] = addr
+			return fields, name, err
+		}
+	}
+	name := d.Get("name").(string)
+	fields["name"] = name
+	return fields, name, nil
+}
+
+// resourcePtrRecordSetState copies a remote record into state
+func resourcePtrRecordSetState(d *schema.ResourceData, m interface{}, i infoblox.Result) diag.Diagnostics {
+	return setRecordState(d, m, "ptr", i, map[string]interface{}{
+		"ptrdname": i.Ptrdname,
+		"name":     i.Name,
+		"ipv4addr": i.Ipv4addr,
+		"ipv6addr": i.Ipv6addr,
+	})
+}
+
+func resourcePtrRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return readRecord(ctx, d, m, resourcePtrRecordSetState)
+}
+
+func resourcePtrRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	fields, _, err := resourcePtrRecordFields(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if diags := updateRecord(ctx, d, m, "ptr", []string{"ptrdname", "ipv4addr", "ipv6addr", "name"}, fields); diags.HasError() {
+		return diags
+	}
+	return resourcePtrRecordRead(ctx, d, m)
+}
@@ -0,0 +1,171 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPtrRecord_basic(t *testing.T) {
+	s := testAccServer(t)
+	var ref, current string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:ptr"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv4addr = "10.0.0.1"`, "host.example.com", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_ptr_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "name", "1.0.0.10.in-addr.arpa"),
+					testAccCheckRecordRemote(s, &ref, "ptrdname", "host.example.com"),
+					resource.TestCheckResourceAttr("infoblox_ptr_record.test", "name", "1.0.0.10.in-addr.arpa"),
+					resource.TestCheckResourceAttr("infoblox_ptr_record.test", "ipv6addr", ""),
+				),
+			},
+			{
+				// update in place keeps the same record
+				Config: testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv4addr = "10.0.0.1"`, "other.example.com", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_ptr_record.test", &current),
+					resource.TestCheckResourceAttrPtr("infoblox_ptr_record.test", "id", &ref),
+					testAccCheckRecordRemote(s, &ref, "ptrdname", "other.example.com"),
+				),
+			},
+			{
+				// a new address moves the record to its reverse name
+				Config: testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv4addr = "10.0.0.2"`, "other.example.com", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_ptr_record.test", &current),
+					testAccCheckRecordRemote(s, &current, "ipv4addr", "10.0.0.2"),
+					testAccCheckRecordRemote(s, &current, "name", "2.0.0.10.in-addr.arpa"),
+					resource.TestCheckResourceAttr("infoblox_ptr_record.test", "name", "2.0.0.10.in-addr.arpa"),
+				),
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv4addr = "10.0.0.2"`, "other.example.com", "Internal"),
+				ResourceName:            "infoblox_ptr_record.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv4addr = "10.0.0.2"`, "other.example.com", "Internal"),
+				ResourceName:            "infoblox_ptr_record.test",
+				ImportState:             true,
+				ImportStateId:           "Internal/2.0.0.10.in-addr.arpa/other.example.com",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				// changing view replaces the record
+				Config: testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv4addr = "10.0.0.2"`, "other.example.com", "External"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_ptr_record.test", &current),
+					testAccCheckRecordRemote(s, &current, "view", "External"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPtrRecord_ipv6(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+	config := testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv6addr = "2001:0db8:0000:0000:0000:0000:0000:0001"`, "host.example.com", "Internal")
+	reverse := "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:ptr"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_ptr_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "ipv6addr", "2001:db8::1"),
+					testAccCheckRecordRemote(s, &ref, "name", reverse),
+					resource.TestCheckResourceAttr("infoblox_ptr_record.test", "ipv6addr", "2001:db8::1"),
+					resource.TestCheckResourceAttr("infoblox_ptr_record.test", "name", reverse),
+				),
+			},
+			{
+				// the compressed address infoblox reports isn't a difference
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				// moving to an IPv4 address clears the IPv6 one
+				Config: testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv4addr = "10.0.0.1"`, "host.example.com", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_ptr_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "name", "1.0.0.10.in-addr.arpa"),
+					resource.TestCheckResourceAttr("infoblox_ptr_record.test", "ipv4addr", "10.0.0.1"),
+					resource.TestCheckResourceAttr("infoblox_ptr_record.test", "ipv6addr", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPtrRecord_name(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+	config := testAccProviderConfig(s) + testAccPtrRecordConfig(`name = "9.13.168.192.in-addr.arpa"`, "host.example.com", "Internal")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:ptr"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_ptr_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "name", "9.13.168.192.in-addr.arpa"),
+					resource.TestCheckResourceAttr("infoblox_ptr_record.test", "name", "9.13.168.192.in-addr.arpa"),
+				),
+			},
+			{
+				PreConfig:          testAccEditRemote(t, s, &ref, map[string]interface{}{"ptrdname": "edited.example.com"}),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check:  testAccCheckRecordRemote(s, &ref, "ptrdname", "host.example.com"),
+			},
+		},
+	})
+}
+
+func TestAccPtrRecord_target(t *testing.T) {
+	s := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv4addr = "10.0.0.1"`+"\n  "+`name = "1.0.0.10.in-addr.arpa"`, "host.example.com", "Internal"),
+				ExpectError: regexp.MustCompile(`only one of .ipv4addr,ipv6addr,name. can be specified`),
+			},
+			{
+				Config:      testAccProviderConfig(s) + testAccPtrRecordConfig(`ipv6addr = "10.0.0.1"`, "host.example.com", "Internal"),
+				ExpectError: regexp.MustCompile(`expected ipv6addr to contain a valid IPv6 address`),
+			},
+		},
+	})
+}
+
+func testAccPtrRecordConfig(target string, ptrdname string, view string) string {
+	return fmt.Sprintf(`
+resource "infoblox_ptr_record" "test" {
+  %s
+  ptrdname = %q
+  view     = %q
+}
+`, target, ptrdname, view)
+}
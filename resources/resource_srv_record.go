@@ -0,0 +1,105 @@
+package resources
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hiscox/terraform-provider-infoblox/infoblox"
+)
+
+// srvRecordKeys are the fields identifying one SRV record among several sharing a name, in the
+// order they are given in DNS and in import IDs
+var srvRecordKeys = []string{"priority", "weight", "port", "target"}
+
+func resourceSrvRecord() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSrvRecordCreate,
+		ReadContext:   resourceSrvRecordRead,
+		UpdateContext: resourceSrvRecordUpdate,
+		DeleteContext: deleteRecord,
+		Importer:      recordImporter("srv", resourceSrvRecordSetState, srvRecordKeys...),
+
+		Timeouts: recordTimeouts(),
+
+		Schema: recordSchema("srv", map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Service, protocol and domain, such as _sip._tcp.example.com",
+			},
+			"priority": srvNumberSchema("Priority of the target, lower values are tried first"),
+			"weight":   srvNumberSchema("Share of the requests the target gets among targets of the same priority"),
+			"port":     srvNumberSchema("Port the service listens on"),
+			"target": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Host providing the service",
+			},
+		}),
+	}
+}
+
+// srvNumberSchema is an SRV field held in 16 bits
+func srvNumberSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:             schema.TypeInt,
+		Required:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(0, 65535)),
+		Description:      description,
+	}
+}
+
+func resourceSrvRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	fields := resourceSrvRecordFields(d)
+	body, bodyUp := recordCreateBodies(d, "srv", fields)
+
+	// several SRV records share a name, they are only told apart by all their fields
+	identity := map[string]string{
+		"name":   fields["name"].(string),
+		"target": fields["target"].(string),
+		"view":   d.Get("view").(string),
+	}
+	for _, k := range []string{"priority", "weight", "port"} {
+		identity[k] = strconv.Itoa(fields[k].(int))
+	}
+	if diags := createRecord(ctx, d, m, "srv", identity, body, bodyUp); diags.HasError() {
+		return diags
+	}
+	return resourceSrvRecordRead(ctx, d, m)
+}
+
+// resourceSrvRecordFields returns the record:srv fields to send to infoblox
+func resourceSrvRecordFields(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     d.Get("name").(string),
+		"priority": d.Get("priority").(int),
+		"weight":   d.Get("weight").(int),
+		"port":     d.Get("port").(int),
+		"target":   d.Get("target").(string),
+	}
+}
+
+// resourceSrvRecordSetState copies a remote record into state
+func resourceSrvRecordSetState(d *schema.ResourceData, m interface{}, i infoblox.Result) diag.Diagnostics {
+	return setRecordState(d, m, "srv", i, map[string]interface{}{
+		"name":     i.Name,
+		"priority": i.Priority,
+		"weight":   i.Weight,
+		"port":     i.Port,
+		"target":   i.Target,
+	})
+}
+
+func resourceSrvRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return readRecord(ctx, d, m, resourceSrvRecordSetState)
+}
+
+func resourceSrvRecordUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := updateRecord(ctx, d, m, "srv", append([]string{"name"}, srvRecordKeys...), resourceSrvRecordFields(d)); diags.HasError() {
+		return diags
+	}
+	return resourceSrvRecordRead(ctx, d, m)
+}
@@ -0,0 +1,143 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSrvRecord_basic(t *testing.T) {
+	s := testAccServer(t)
+	var ref, current string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:srv"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccSrvRecordConfig("test", 10, 60, 5060, "sip1.example.com", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_srv_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "priority", 10),
+					testAccCheckRecordRemote(s, &ref, "weight", 60),
+					testAccCheckRecordRemote(s, &ref, "port", 5060),
+					testAccCheckRecordRemote(s, &ref, "target", "sip1.example.com"),
+					resource.TestCheckResourceAttr("infoblox_srv_record.test", "name", "_sip._tcp.example.com"),
+				),
+			},
+			{
+				// update in place keeps the same record
+				Config: testAccProviderConfig(s) + testAccSrvRecordConfig("test", 10, 40, 5061, "sip1.example.com", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_srv_record.test", &current),
+					resource.TestCheckResourceAttrPtr("infoblox_srv_record.test", "id", &ref),
+					testAccCheckRecordRemote(s, &ref, "weight", 40),
+					testAccCheckRecordRemote(s, &ref, "port", 5061),
+				),
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccSrvRecordConfig("test", 10, 40, 5061, "sip1.example.com", "Internal"),
+				ResourceName:            "infoblox_srv_record.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccSrvRecordConfig("test", 10, 40, 5061, "sip1.example.com", "Internal"),
+				ResourceName:            "infoblox_srv_record.test",
+				ImportState:             true,
+				ImportStateId:           "Internal/_sip._tcp.example.com/10/40/5061/sip1.example.com",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				// changing view replaces the record
+				Config: testAccProviderConfig(s) + testAccSrvRecordConfig("test", 10, 40, 5061, "sip1.example.com", "External"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_srv_record.test", &current),
+					testAccCheckRefChanged(s, &ref, &current),
+					testAccCheckRecordRemote(s, &current, "view", "External"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSrvRecord_shareName(t *testing.T) {
+	s := testAccServer(t)
+	var first, second string
+	// only the port differs from the first record in the configuration
+	if _, err := s.Create("record:srv", map[string]interface{}{
+		"name": "_sip._tcp.example.com", "priority": 10, "weight": 60, "port": 5070, "target": "sip1.example.com", "view": "Internal",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	config := testAccProviderConfig(s) +
+		testAccSrvRecordConfig("first", 10, 60, 5060, "sip1.example.com", "Internal") +
+		testAccSrvRecordConfig("second", 20, 0, 5060, "sip2.example.com", "Internal")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_srv_record.first", &first),
+					testAccCheckRecordExists(s, "infoblox_srv_record.second", &second),
+					testAccCheckRecordRemote(s, &first, "port", 5060),
+					testAccCheckRecordRemote(s, &second, "target", "sip2.example.com"),
+				),
+			},
+			{
+				// the import ID picks out the record on port 5060 rather than the one on 5070
+				Config:                  config,
+				ResourceName:            "infoblox_srv_record.first",
+				ImportState:             true,
+				ImportStateId:           "Internal/_sip._tcp.example.com/10/60/5060",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				Config:      config + testAccSrvRecordConfig("duplicate", 10, 60, 5070, "sip1.example.com", "Internal"),
+				ExpectError: regexp.MustCompile(`already exists in infoblox`),
+			},
+		},
+	})
+}
+
+func TestAccSrvRecord_ranges(t *testing.T) {
+	s := testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(s) + testAccSrvRecordConfig("test", -1, 0, 5060, "sip1.example.com", "Internal"),
+				ExpectError: regexp.MustCompile(`expected priority to be in the range \(0 - 65535\)`),
+			},
+			{
+				Config:      testAccProviderConfig(s) + testAccSrvRecordConfig("test", 0, 65536, 5060, "sip1.example.com", "Internal"),
+				ExpectError: regexp.MustCompile(`expected weight to be in the range \(0 - 65535\)`),
+			},
+			{
+				Config:      testAccProviderConfig(s) + testAccSrvRecordConfig("test", 0, 0, 70000, "sip1.example.com", "Internal"),
+				ExpectError: regexp.MustCompile(`expected port to be in the range \(0 - 65535\)`),
+			},
+		},
+	})
+}
+
+func testAccSrvRecordConfig(resourceName string, priority int, weight int, port int, target string, view string) string {
+	return fmt.Sprintf(`
+resource "infoblox_srv_record" %q {
+  name     = "_sip._tcp.example.com"
+  priority = %d
+  weight   = %d
+  port     = %d
+  target   = %q
+  view     = %q
+}
+`, resourceName, priority, weight, port, target, view)
+}
@@ -0,0 +1,153 @@
+package resources
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccTxtRecord_basic(t *testing.T) {
+	s := testAccServer(t)
+	var ref, current string
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:txt"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccTxtRecordConfig("v=spf1 -all", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_txt_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "text", "v=spf1 -all"),
+					resource.TestCheckResourceAttr("infoblox_txt_record.test", "name", "host.example.com"),
+					resource.TestCheckResourceAttr("infoblox_txt_record.test", "view", "Internal"),
+				),
+			},
+			{
+				// update in place keeps the same record
+				Config: testAccProviderConfig(s) + testAccTxtRecordConfig("v=spf1 mx -all", "Internal"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_txt_record.test", &current),
+					resource.TestCheckResourceAttrPtr("infoblox_txt_record.test", "id", &ref),
+					testAccCheckRecordRemote(s, &ref, "text", "v=spf1 mx -all"),
+				),
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccTxtRecordConfig("v=spf1 mx -all", "Internal"),
+				ResourceName:            "infoblox_txt_record.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				Config:                  testAccProviderConfig(s) + testAccTxtRecordConfig("v=spf1 mx -all", "Internal"),
+				ResourceName:            "infoblox_txt_record.test",
+				ImportState:             true,
+				ImportStateId:           "Internal/host.example.com/v=spf1 mx -all",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"on_conflict"},
+			},
+			{
+				// changing view replaces the record
+				Config: testAccProviderConfig(s) + testAccTxtRecordConfig("v=spf1 mx -all", "External"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_txt_record.test", &current),
+					testAccCheckRefChanged(s, &ref, &current),
+					testAccCheckRecordRemote(s, &current, "view", "External"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTxtRecord_drift(t *testing.T) {
+	s := testAccServer(t)
+	var ref string
+	config := testAccProviderConfig(s) + testAccTxtRecordConfig("managed", "Internal")
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:txt"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckRecordExists(s, "infoblox_txt_record.test", &ref),
+			},
+			{
+				PreConfig:          testAccEditRemote(t, s, &ref, map[string]interface{}{"text": "edited by hand"}),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordRemote(s, &ref, "text", "managed"),
+					resource.TestCheckResourceAttr("infoblox_txt_record.test", "text", "managed"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTxtRecord_commonFields(t *testing.T) {
+	s := testAccServer(t)
+	var ref, current string
+	config := testAccProviderConfig(s) + testAccTxtRecordCommonConfig("Test of automation", false)
+
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories(),
+		CheckDestroy:      testAccCheckRecordDestroyed(s, "record:txt"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_txt_record.test", &ref),
+					testAccCheckRecordRemote(s, &ref, "comment", "Test of automation"),
+					testAccCheckRecordRemote(s, &ref, "disable", false),
+					testAccCheckRecordRemote(s, &ref, "creator", "STATIC"),
+					resource.TestCheckResourceAttr("infoblox_txt_record.test", "creator", "STATIC"),
+				),
+			},
+			{
+				PreConfig:          testAccEditRemote(t, s, &ref, map[string]interface{}{"comment": "edited by hand"}),
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccTxtRecordCommonConfig("Changed", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRecordExists(s, "infoblox_txt_record.test", &current),
+					resource.TestCheckResourceAttrPtr("infoblox_txt_record.test", "id", &ref),
+					testAccCheckRecordRemote(s, &ref, "comment", "Changed"),
+					testAccCheckRecordRemote(s, &ref, "disable", true),
+					resource.TestCheckResourceAttr("infoblox_txt_record.test", "disable", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccTxtRecordCommonConfig(comment string, disable bool) string {
+	return fmt.Sprintf(`
+resource "infoblox_txt_record" "test" {
+  name    = "host.example.com"
+  text    = "v=spf1 -all"
+  comment = %q
+  disable = %t
+  view    = "Internal"
+}
+`, comment, disable)
+}
+
+func testAccTxtRecordConfig(text string, view string) string {
+	return fmt.Sprintf(`
+resource "infoblox_txt_record" "test" {
+  name = "host.example.com"
+  text = %q
+  view = %q
+}
+`, text, view)
+}